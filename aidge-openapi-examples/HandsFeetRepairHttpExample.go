@@ -1,3 +1,5 @@
+//go:build ignore
+
 /*
 Copyright (C) 2024 NEURALNETICS PTE. LTD.
 
@@ -13,174 +15,89 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
+
 package main
 
 import (
-	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"strconv"
-	"time"
 	"os"
-	"strings"
-)
-
-// ApiConfig struct holds configuration for API access
-type ApiConfig struct {
-	AccessKeyName   string
-	AccessKeySecret string
-	ApiDomain       string
-	UseTrialResource bool
-}
-
-// Global variable to hold the API configuration
-var apiConfig = ApiConfig{
-    // Your personal data
-	AccessKeyName:   os.Getenv("accessKey"), // e.g. "512345"
-	AccessKeySecret: os.Getenv("secret"),
-
-	/* "api.aidc-ai.com" for api purchased on global site
-	 * 中文站购买的API请使用"cn-api.aidc-ai.com" (for api purchased on chinese site)
-	 */
-	ApiDomain:       "your api domain",
-
-	/**
-	 * We offer trial quota to help you familiarize and test how to use the Aidge API in your account
-	 * To use trial quota, please set useTrialResource to true
-	 * If you set useTrialResource to false before you purchase the API
-	 * You will receive "Sorry, your calling resources have been exhausted........"
-	 * 我们为您的账号提供一定数量的免费试用额度可以试用任何API。请将useTrialResource设置为true用于试用。
-	 * 如设置为false，且您未购买该API，将会收到"Sorry, your calling resources have been exhausted........."的错误提示
-	 */
-	UseTrialResource: false / true,
-
-	/**
-    * FAQ for API response
-	* FAQ:https://app.gitbook.com/o/pBUcuyAewroKoYr3CeVm/s/cXGtrD26wbOKouIXD83g/getting-started/faq
-    * FAQ(中文/Simple Chinese):https://aidge.yuque.com/org-wiki-aidge-bzb63a/brbggt/ny2tgih89utg1aha
-    */
-}
-
-// Function to invoke the API
-func invokeAPI(apiName string, data []byte) (string, error) {
-	// Timestamp in milliseconds
-	timestamp := fmt.Sprintf("%d", time.Now().UnixNano()/int64(time.Millisecond))
-
-	// Calculate SHA256 HMAC
-	h := hmac.New(sha256.New, []byte(apiConfig.AccessKeySecret))
-	h.Write([]byte(apiConfig.AccessKeySecret + timestamp))
-	sign := strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
-
-	url := fmt.Sprintf("https://%s/rest%s?partner_id=aidge&sign_method=sha256&sign_ver=v2&app_key=%s&timestamp=%s&sign=%s",
-		apiConfig.ApiDomain, apiName, apiConfig.AccessKeyName, timestamp, sign)
-
-	// Add "x-iop-trial": "true" for trial
-	headers := map[string]string{
-		"Content-Type": "application/json",
-		"x-iop-trial":  strconv.FormatBool(apiConfig.UseTrialResource),
-	}
-
-	// HTTP request
-	request, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	if err != nil {
-		return "", err
-	}
-
-	for key, value := range headers {
-		request.Header.Set(key, value)
-	}
+	"time"
 
-	client := &http.Client{}
-	response, err := client.Do(request)
-	if err != nil {
-		return "", err
-	}
-	defer response.Body.Close()
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
 
-	body, err := ioutil.ReadAll(response.Body)
+func main() {
+	// Record submitted tasks in a local journal, so that a task is not lost if this process stops before it finishes
+	store, err := aidge.OpenFileTaskStore("aidge-tasks.jsonl")
 	if err != nil {
-		return "", err
+		fmt.Println("Error opening task journal:", err)
+		return
 	}
+	defer store.Close()
+
+	client := aidge.NewClient(aidge.ApiConfig{
+		// Personal data from environment variables
+		AccessKeyName:   os.Getenv("accessKey"), // e.g. "512345"
+		AccessKeySecret: os.Getenv("secret"),
+
+		/* "api.aidc-ai.com" for api purchased on global site
+		 * 中文站购买的API请使用"cn-api.aidc-ai.com" (for api purchased on chinese site)
+		 */
+		ApiDomain: "your api domain",
+
+		/**
+		 * We offer trial quota to help you familiarize and test how to use the Aidge API in your account
+		 * To use trial quota, please set UseTrialResource to true
+		 * If you set UseTrialResource to false before you purchase the API
+		 * You will receive "Sorry, your calling resources have been exhausted........"
+		 * 我们为您的账号提供一定数量的免费试用额度可以试用任何API。请将UseTrialResource设置为true用于试用。
+		 * 如设置为false，且您未购买该API，将会收到"Sorry, your calling resources have been exhausted........."的错误提示
+		 */
+		UseTrialResource: false,
+	}, aidge.WithTaskStore(store))
+
+	// Wait for the tasks left unfinished by a previous run, alongside the new task and with a deadline of their own
+	resumed := make(chan struct{})
+	go func() {
+		defer close(resumed)
+		resumeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		records, err := client.Resume(resumeCtx)
+		if err != nil {
+			fmt.Println("Error resuming tasks:", err)
+		}
+		for _, record := range records {
+			fmt.Println("Resumed task", record.ID, "of", record.API+":", record.Status, record.Error)
+		}
+	}()
+	defer func() { <-resumed }()
 
-	return string(body), nil
-}
-
-func main() {
-	// Call api
-	apiName := "/ai/hand-foot/repair"
+	// Bound the whole submit and wait, the task is abandoned after 10 minutes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
 
-	// Constructor request Parameters
-	paramJson := []map[string]interface{}{
+	// Call hands and feet repair submit and wait for the task to finish
+	results, err := client.HandFootRepair.Repair(ctx, []aidge.RepairItem{
 		{
-			"area":         "hand",
-			"imageUrl":     "your imageUrl",
-			"imgNum":       "1",
-			"requestBizId": "",
+			Area:       aidge.AreaHand,
+			ImageURL:   "your imageUrl",
+			ImageCount: 1,
+			// A unique requestBizId lets the gateway drop duplicates, so the submit may be retried
+			RequestBizID: "your unique business id",
 		},
-	}
-
-	param := map[string]interface{}{
-		"paramJson": paramJson,
-	}
-
-	// Convert parameters to JSON string
-	request, err := json.Marshal(param)
-	if err != nil {
-		fmt.Println("Error marshaling JSON:", err)
-		return
-	}
-
-	submitResult, err := invokeAPI(apiName, request)
+	})
 	if err != nil {
 		fmt.Println("Error invoking API:", err)
 		return
 	}
 
-	var submitResultJson map[string]interface{}
-	err = json.Unmarshal([]byte(submitResult), &submitResultJson)
-	if err != nil {
-		fmt.Println("Error unmarshaling JSON:", err)
-		return
-	}
-	fmt.Println(submitResultJson)
-
-	taskID := submitResultJson["data"].(map[string]interface{})["result"].(map[string]interface{})["taskId"].(string)
-
-	// Query task status
-	queryApiName := "/ai/hand-foot/repair-results"
-	queryRequest, err := json.Marshal(map[string]string{"taskId": taskID})
-	if err != nil {
-		fmt.Println("Error marshaling JSON:", err)
-		return
-	}
-
-	for {
-		queryResult, err := invokeAPI(queryApiName, queryRequest)
-		if err != nil {
-			fmt.Println("Error invoking API:", err)
-			return
-		}
-
-		var queryResultJson map[string]interface{}
-		err = json.Unmarshal([]byte(queryResult), &queryResultJson)
-		if err != nil {
-			fmt.Println("Error unmarshaling JSON:", err)
-			return
+	// Final result for the hands and feet repair
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Println(result.Item.RequestBizID, "failed:", result.Err)
+			continue
 		}
-		fmt.Println("queryResultJson=", queryResultJson)
-
-		taskStatus := queryResultJson["data"].(map[string]interface{})["taskStatus"].(string)
-		if taskStatus == "finished" {
-			break
-		}
-		time.Sleep(5 * time.Second)
+		fmt.Println(result.Item.RequestBizID, result.ImageURLs)
 	}
-
-	// Add a small delay between requests to avoid overwhelming the API
-	time.Sleep(1 * time.Second)
 }
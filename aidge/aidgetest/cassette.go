@@ -0,0 +1,289 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidgetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode is the mode of a Cassette.
+type Mode int
+
+const (
+	// ModeReplay answers the calls with the recorded responses.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the calls to the gateway and records them.
+	ModeRecord
+
+	// ModeAuto replays the cassette when its file exists and records it
+	// otherwise.
+	ModeAuto
+)
+
+// scrubbedParams are the query parameters left out of a cassette.
+var scrubbedParams = []string{"sign", "app_key", "timestamp"}
+
+// scrubbedHeaders are the headers left out of a cassette.
+var scrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Interaction is a call recorded in a cassette.
+type Interaction struct {
+	APIName string     `json:"apiName"`
+	Method  string     `json:"method"`
+	Query   url.Values `json:"query,omitempty"`
+
+	// Request and Response hold the bodies.
+	Request  Body `json:"request,omitempty"`
+	Response Body `json:"response,omitempty"`
+
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Body is an HTTP body, kept as JSON in the cassette file when it is JSON
+// and as a string otherwise.
+type Body []byte
+
+// MarshalJSON encodes b as JSON when it is JSON, as a string otherwise.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON decodes b from a string or any other JSON value.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Cassette is an http.RoundTripper recording the calls of a client to a
+// file, and replaying them in place of the gateway. The signature, API key
+// and timestamp of the calls and the credential headers are never recorded.
+//
+// In replay mode a call is answered with the first unused interaction of the
+// same API and the same request body, once normalized. A call without one
+// fails with an error, which Err reports as well.
+//
+//	cassette, err := aidgetest.OpenCassette("testdata/cutout.json", aidgetest.ModeAuto, nil)
+//	...
+//	client := aidge.NewClient(config, aidge.WithHTTPClient(&http.Client{Transport: cassette}))
+//	...
+//	err = cassette.Close()
+type Cassette struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	unmatched    []error
+}
+
+// OpenCassette opens the cassette file at path. In record mode, calls are
+// sent with transport, http.DefaultTransport when nil, and the file is
+// written by Close.
+func OpenCassette(path string, mode Mode, transport http.RoundTripper) (*Cassette, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := &Cassette{path: path, mode: mode, transport: transport}
+	if mode == ModeAuto {
+		c.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			c.mode = ModeRecord
+		}
+	}
+	if c.mode == ModeRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("aidgetest: %w", err)
+	}
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("aidgetest: reading cassette %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return c, nil
+}
+
+// Mode returns the mode of the cassette, ModeReplay or ModeRecord.
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// RoundTrip records or replays req.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	call := Interaction{
+		APIName: strings.TrimPrefix(req.URL.Path, "/rest"),
+		Method:  req.Method,
+		Query:   scrub(req.URL.Query()),
+		Request: body,
+	}
+	if c.mode == ModeReplay {
+		return c.replay(req, call)
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	call.Status = resp.StatusCode
+	call.Header = resp.Header.Clone()
+	for _, h := range scrubbedHeaders {
+		call.Header.Del(h)
+	}
+	call.Response = respBody
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, call)
+	c.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay answers req with the first unused interaction matching call.
+func (c *Cassette) replay(req *http.Request, call Interaction) (*http.Response, error) {
+	key := matchKey(call)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, recorded := range c.interactions {
+		if c.used[i] || matchKey(recorded) != key {
+			continue
+		}
+		c.used[i] = true
+		header := recorded.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+			StatusCode:    recorded.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(recorded.Response)),
+			ContentLength: int64(len(recorded.Response)),
+			Request:       req,
+		}, nil
+	}
+	err := fmt.Errorf("aidgetest: cassette %s has no response for %s %s with body %s", c.path, call.Method, call.APIName, normalize(call.Request))
+	c.unmatched = append(c.unmatched, err)
+	return nil, err
+}
+
+// Err returns the calls the cassette could not replay.
+func (c *Cassette) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return errors.Join(c.unmatched...)
+}
+
+// Close writes the cassette file in record mode. In replay mode it returns
+// Err.
+func (c *Cassette) Close() error {
+	if c.mode == ModeReplay {
+		return c.Err()
+	}
+	c.mu.Lock()
+	file := struct {
+		Interactions []Interaction `json:"interactions"`
+	}{c.interactions}
+	data, err := json.MarshalIndent(file, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("aidgetest: writing cassette %s: %w", c.path, err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("aidgetest: %w", err)
+	}
+	return nil
+}
+
+// scrub removes the credentials from query.
+func scrub(query url.Values) url.Values {
+	for _, p := range scrubbedParams {
+		query.Del(p)
+	}
+	return query
+}
+
+// matchKey identifies the calls answered by the same interaction.
+func matchKey(call Interaction) string {
+	return call.APIName + "?" + call.Query.Encode() + " " + normalize(call.Request)
+}
+
+// normalize returns body with sorted keys and without spaces when it is
+// JSON, as is otherwise.
+func normalize(body []byte) string {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+	normalized, _ := json.Marshal(v)
+	return string(normalized)
+}
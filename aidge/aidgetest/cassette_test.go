@@ -0,0 +1,133 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidgetest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// cassetteClient returns a client of srv sending its calls through cassette.
+func cassetteClient(srv *aidgetest.Server, cassette *aidgetest.Cassette) *aidge.Client {
+	return aidge.NewClient(srv.Config(),
+		aidge.WithHTTPClient(&http.Client{Transport: cassette}),
+		aidge.WithRetry(aidge.RetryPolicy{}),
+	)
+}
+
+func TestCassetteRecordsWithoutCredentials(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/big.png"}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	request := map[string]interface{}{"imageUrl": "https://example.com/a.jpg", "upscaleFactor": 2}
+
+	cassette, err := aidgetest.OpenCassette(path, aidgetest.ModeAuto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cassette.Mode() != aidgetest.ModeRecord {
+		t.Fatalf("mode of a new cassette = %v, want ModeRecord", cassette.Mode())
+	}
+	if err := cassetteClient(srv, cassette).Do(context.Background(), aidge.ImageUpscalingAPI, request, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := cassette.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file struct {
+		Interactions []aidgetest.Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Interactions) != 1 {
+		t.Fatalf("%d interactions, want 1", len(file.Interactions))
+	}
+	for _, p := range []string{"sign", "app_key", "timestamp"} {
+		if file.Interactions[0].Query.Has(p) {
+			t.Errorf("cassette keeps %s", p)
+		}
+	}
+	if sign := srv.Calls()[0].Query.Get("sign"); strings.Contains(string(data), sign) {
+		t.Errorf("cassette holds the signature %s", sign)
+	}
+}
+
+func TestCassetteReplay(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/big.png"}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	recorder, err := aidgetest.OpenCassette(path, aidgetest.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cassetteClient(srv, recorder).Do(ctx, aidge.ImageUpscalingAPI, map[string]int{"upscaleFactor": 2}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette, err := aidgetest.OpenCassette(path, aidgetest.ModeAuto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := cassetteClient(srv, cassette)
+	var data struct {
+		ImageURL string `json:"imageUrl"`
+	}
+	// The recorded call is matched whatever the spacing and key order of its body.
+	if err := client.Do(ctx, aidge.ImageUpscalingAPI, json.RawMessage(`{ "upscaleFactor" : 2 }`), &data); err != nil {
+		t.Fatalf("replayed call: %v", err)
+	}
+	if data.ImageURL != "https://example.com/big.png" {
+		t.Errorf("replayed imageUrl = %q", data.ImageURL)
+	}
+	if n := len(srv.Calls()); n != 1 {
+		t.Errorf("the server got %d calls, want only the recorded one", n)
+	}
+
+	// Each interaction answers one call, and other requests have none.
+	for _, factor := range []int{2, 4} {
+		if err := client.Do(ctx, aidge.ImageUpscalingAPI, map[string]int{"upscaleFactor": factor}, nil); err == nil {
+			t.Errorf("call with upscaleFactor %d replayed, want a miss", factor)
+		}
+	}
+	if err := cassette.Err(); err == nil || !strings.Contains(err.Error(), `{"upscaleFactor":4}`) {
+		t.Errorf("Err = %v, want the missed calls", err)
+	}
+	if err := cassette.Close(); err == nil {
+		t.Error("Close of a cassette with misses succeeded")
+	}
+}
@@ -0,0 +1,375 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aidgetest provides a fake Aidge gateway for testing code that calls
+// Aidge APIs without reaching api.aidc-ai.com.
+//
+// The Server verifies the signature of every call, as the gateway does, and
+// answers with the replies scripted for each API:
+//
+//	srv := aidgetest.NewServer()
+//	defer srv.Close()
+//	srv.Reply(aidge.BackgroundRemovalAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/out.png"}))
+//	srv.Task(aidge.HandFootRepairTask, aidgetest.Running(), aidgetest.Finished(map[string]interface{}{
+//		"result": []map[string]interface{}{{"imageUrls": []string{"https://example.com/repaired.png"}}},
+//	}))
+//	client := srv.Client()
+//
+// Tasks are polled at the pace of their TaskSpec; tests of the typed services
+// usually give the client a short poll instead:
+//
+//	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+//
+// A Cassette records the calls made to the real gateway once and replays
+// them afterwards.
+package aidgetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
+
+// Default credentials of a Server.
+const (
+	DefaultAccessKeyName   = "512345"
+	DefaultAccessKeySecret = "aidgetest-secret"
+)
+
+// Call is a call received by a Server.
+type Call struct {
+	// APIName is the API called, e.g. "/ai/image/cut/out".
+	APIName string
+
+	Method string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+
+	// Trial tells that the call was made with the x-iop-trial header.
+	Trial bool
+}
+
+// Reply is the answer of a Server to a call.
+type Reply struct {
+	// Status is the HTTP status, 200 when zero.
+	Status int
+
+	// Code, Message and Type are the fields of the response envelope,
+	// Code "0" when empty.
+	Code    string
+	Message string
+	Type    string
+
+	// Data is encoded with json.Marshal as the data of the envelope.
+	Data interface{}
+
+	// Header is added to the HTTP response, e.g. Retry-After.
+	Header http.Header
+
+	// Delay holds the response back, to test timeouts.
+	Delay time.Duration
+}
+
+// OK returns a successful reply with data.
+func OK(data interface{}) Reply {
+	return Reply{Data: data}
+}
+
+// Fail returns a failed reply with the given error code and message, e.g.
+// Fail("InvalidParameter", "imageUrl is invalid").
+func Fail(code, message string) Reply {
+	return Reply{Code: code, Message: message, Type: "ISV"}
+}
+
+// TaskStep is the state of a task returned by one query of its results.
+type TaskStep struct {
+	// Status is the taskStatus of the task.
+	Status aidge.TaskStatus
+
+	// Data holds the other fields of the results, e.g. "result".
+	Data map[string]interface{}
+
+	// ErrorCode and ErrorMessage describe the failure of a failed task.
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// Running returns the step of a task in progress.
+func Running() TaskStep {
+	return TaskStep{Status: aidge.TaskRunning}
+}
+
+// Finished returns the step of a finished task with the results in data.
+func Finished(data map[string]interface{}) TaskStep {
+	return TaskStep{Status: aidge.TaskFinished, Data: data}
+}
+
+// Failed returns the step of a failed task.
+func Failed(code, message string) TaskStep {
+	return TaskStep{Status: aidge.TaskFailed, ErrorCode: code, ErrorMessage: message}
+}
+
+// Server is a fake Aidge gateway. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to be used as the ApiDomain of a
+	// client.
+	URL string
+
+	// AccessKeyName and AccessKeySecret are the key accepted by the
+	// server. Changes must be made before the first call.
+	AccessKeyName   string
+	AccessKeySecret string
+
+	// TrialOnly fails calls made without the x-iop-trial header with the
+	// exhausted resources error, as the gateway does for APIs that have not
+	// been purchased.
+	TrialOnly bool
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]func(Call) Reply
+	tasks    map[string]*task
+	calls    []Call
+	nextID   int
+}
+
+// task is a task submitted to a Server.
+type task struct {
+	steps   []TaskStep
+	queries int
+}
+
+// NewServer starts a Server accepting DefaultAccessKeyName and
+// DefaultAccessKeySecret. The caller must Close it.
+func NewServer() *Server {
+	s := &Server{
+		AccessKeyName:   DefaultAccessKeyName,
+		AccessKeySecret: DefaultAccessKeySecret,
+		handlers:        map[string]func(Call) Reply{},
+		tasks:           map[string]*task{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns the configuration of a client of the server.
+func (s *Server) Config() aidge.ApiConfig {
+	return aidge.ApiConfig{
+		AccessKeyName:   s.AccessKeyName,
+		AccessKeySecret: s.AccessKeySecret,
+		ApiDomain:       s.URL,
+	}
+}
+
+// Client returns a client of the server.
+func (s *Server) Client(opts ...aidge.Option) *aidge.Client {
+	return aidge.NewClient(s.Config(), append([]aidge.Option{aidge.WithHTTPClient(s.srv.Client())}, opts...)...)
+}
+
+// Reply scripts the replies to the calls of apiName, in order. The last
+// reply answers the calls after it.
+func (s *Server) Reply(apiName string, replies ...Reply) {
+	if len(replies) == 0 {
+		panic("aidgetest: no replies for " + apiName)
+	}
+	var n int
+	s.HandleFunc(apiName, func(Call) Reply {
+		reply := replies[min(n, len(replies)-1)]
+		n++
+		return reply
+	})
+}
+
+// HandleFunc answers the calls of apiName with f. f is called with the lock
+// of the server held and must not call its methods.
+func (s *Server) HandleFunc(apiName string, f func(Call) Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[apiName] = f
+}
+
+// Task scripts the task API of spec. Each submit creates a task whose
+// queries go through steps, in order; the last step answers the queries
+// after it. Reply or HandleFunc on the submit API afterwards makes submits
+// fail.
+func (s *Server) Task(spec aidge.TaskSpec, steps ...TaskStep) {
+	if len(steps) == 0 {
+		panic("aidgetest: no steps for " + spec.SubmitAPI)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[spec.SubmitAPI] = func(Call) Reply {
+		s.nextID++
+		id := "task-" + strconv.Itoa(s.nextID)
+		s.tasks[spec.SubmitAPI+" "+id] = &task{steps: steps}
+		return OK(map[string]interface{}{"result": map[string]string{"taskId": id}})
+	}
+	s.handlers[spec.ResultAPI] = func(call Call) Reply {
+		id := call.Query.Get(spec.IDField)
+		if id == "" {
+			var params map[string]interface{}
+			json.Unmarshal(call.Body, &params)
+			if v, ok := params[spec.IDField]; ok {
+				id = fmt.Sprint(v)
+			}
+		}
+		t := s.tasks[spec.SubmitAPI+" "+id]
+		if t == nil {
+			return Fail("InvalidParameter", fmt.Sprintf("%s %q not found", spec.IDField, id))
+		}
+		step := t.steps[min(t.queries, len(t.steps)-1)]
+		t.queries++
+
+		data := map[string]interface{}{}
+		for k, v := range step.Data {
+			data[k] = v
+		}
+		data["taskStatus"] = string(step.Status)
+		if step.ErrorCode != "" {
+			data["errorCode"] = step.ErrorCode
+		}
+		if step.ErrorMessage != "" {
+			data["errorMessage"] = step.ErrorMessage
+		}
+		return OK(data)
+	}
+}
+
+// Calls returns the calls received by the server, in order, including those
+// that were rejected.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the calls of apiName received by the server, in order.
+func (s *Server) CallsTo(apiName string) []Call {
+	var calls []Call
+	for _, call := range s.Calls() {
+		if call.APIName == apiName {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	call := Call{
+		APIName: strings.TrimPrefix(r.URL.Path, "/rest"),
+		Method:  r.Method,
+		Query:   r.URL.Query(),
+		Header:  r.Header.Clone(),
+		Body:    body,
+		Trial:   r.Header.Get("x-iop-trial") == "true",
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	n := len(s.calls)
+	reply := s.reply(call)
+	s.mu.Unlock()
+
+	if reply.Delay > 0 {
+		select {
+		case <-time.After(reply.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	write(w, reply, n)
+}
+
+// reply returns the reply to call. The lock of the server is held.
+func (s *Server) reply(call Call) Reply {
+	if !strings.HasPrefix(call.Header.Get("Content-Type"), "application/json") {
+		return Reply{Status: http.StatusBadRequest, Code: "InvalidRequest", Message: "Content-Type must be application/json"}
+	}
+	if appKey := call.Query.Get("app_key"); appKey != s.AccessKeyName {
+		return Reply{Status: http.StatusUnauthorized, Code: "InvalidAppKey", Message: fmt.Sprintf("app_key %q does not exist", appKey), Type: "ISV"}
+	}
+	signer := aidge.NewSigner(s.AccessKeyName, s.AccessKeySecret)
+	if err := signer.Verify(call.Query); err != nil {
+		code := "InvalidSignature"
+		if call.Query.Get("sign") == "" || call.Query.Get("timestamp") == "" {
+			code = "IncompleteSignature"
+		}
+		return Reply{Status: http.StatusUnauthorized, Code: code, Message: strings.TrimPrefix(err.Error(), "aidge: "), Type: "ISV"}
+	}
+	if s.TrialOnly && !call.Trial {
+		return Fail("InsufficientResource", "Sorry, your calling resources have been exhausted")
+	}
+	handler := s.handlers[call.APIName]
+	if handler == nil {
+		return Reply{Status: http.StatusNotFound, Code: "InvalidApiPath", Message: "aidgetest: no reply scripted for " + call.APIName, Type: "ISV"}
+	}
+	return handler(call)
+}
+
+// write writes reply as a response envelope.
+func write(w http.ResponseWriter, reply Reply, n int) {
+	envelope := map[string]interface{}{
+		"code":       reply.Code,
+		"message":    reply.Message,
+		"request_id": "aidgetest-" + strconv.Itoa(n),
+	}
+	if reply.Code == "" {
+		envelope["code"] = "0"
+	}
+	if reply.Type != "" {
+		envelope["type"] = reply.Type
+	}
+	if reply.Data != nil {
+		envelope["data"] = reply.Data
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for k, v := range reply.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Content-Type", "application/json")
+	status := reply.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidgetest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestServerVerifiesCredentials(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]string{}))
+
+	tests := []struct {
+		name   string
+		config aidge.ApiConfig
+		code   string
+	}{
+		{"unknown app key", aidge.ApiConfig{AccessKeyName: "999", AccessKeySecret: srv.AccessKeySecret}, "InvalidAppKey"},
+		{"wrong secret", aidge.ApiConfig{AccessKeyName: srv.AccessKeyName, AccessKeySecret: "wrong"}, "InvalidSignature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ApiDomain = srv.URL
+			client := aidge.NewClient(tt.config)
+			err := client.Do(context.Background(), aidge.ImageUpscalingAPI, map[string]string{}, nil)
+			var apiErr *aidge.APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.code || apiErr.Kind != aidge.KindAuth {
+				t.Errorf("Do: %v, want an auth failure %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestServerTrialOnly(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.TrialOnly = true
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]string{}))
+	ctx := context.Background()
+
+	err := srv.Client().Do(ctx, aidge.ImageUpscalingAPI, map[string]string{}, nil)
+	var apiErr *aidge.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != aidge.KindQuotaExhausted {
+		t.Errorf("Do without trial: %v, want exhausted resources", err)
+	}
+
+	config := srv.Config()
+	config.UseTrialResource = true
+	trial := aidge.NewClient(config)
+	if err := trial.Do(ctx, aidge.ImageUpscalingAPI, map[string]string{}, nil); err != nil {
+		t.Errorf("Do with trial: %v", err)
+	}
+	if calls := srv.Calls(); len(calls) != 2 || calls[0].Trial || !calls[1].Trial {
+		t.Errorf("calls = %+v, want one without and one with trial", calls)
+	}
+}
+
+func TestServerReplies(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI,
+		aidgetest.Fail("InvalidParameter", "imageUrl is invalid"),
+		aidgetest.OK(map[string]string{"imageUrl": "https://example.com/big.png"}),
+	)
+	client := srv.Client()
+	ctx := context.Background()
+
+	err := client.Do(ctx, aidge.ImageUpscalingAPI, map[string]string{}, nil)
+	var apiErr *aidge.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != aidge.KindInvalidParameter {
+		t.Errorf("first call: %v, want the scripted failure", err)
+	}
+	// The last reply answers the calls after it.
+	for i := 0; i < 2; i++ {
+		var data struct {
+			ImageURL string `json:"imageUrl"`
+		}
+		if err := client.Do(ctx, aidge.ImageUpscalingAPI, map[string]string{}, &data); err != nil || data.ImageURL != "https://example.com/big.png" {
+			t.Errorf("call %d: %+v, %v", i+2, data, err)
+		}
+	}
+
+	err = client.Do(ctx, aidge.BackgroundRemovalAPI, map[string]string{}, nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("unscripted call: %v, want not found", err)
+	}
+}
+
+func TestServerTask(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.ImageTranslationProTask
+	srv.Task(spec, aidgetest.Running(), aidgetest.Running(), aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{{"imageUrl": "https://example.com/fr.png"}},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+	ctx := context.Background()
+
+	var statuses []aidge.TaskStatus
+	task := aidge.NewTask[map[string]interface{}](client, spec)
+	if err := task.Submit(ctx, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		result, err := task.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, result.Status)
+	}
+	want := []aidge.TaskStatus{aidge.TaskRunning, aidge.TaskRunning, aidge.TaskFinished, aidge.TaskFinished}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Fatalf("statuses = %v, want %v", statuses, want)
+		}
+	}
+
+	// Each submit creates a task with its own steps.
+	other := aidge.NewTask[map[string]interface{}](client, spec)
+	if err := other.Submit(ctx, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if other.ID() == task.ID() {
+		t.Errorf("both tasks have id %s", task.ID())
+	}
+	if _, err := other.Wait(ctx); err != nil {
+		t.Errorf("Wait: %v", err)
+	}
+
+	_, err := aidge.AttachTask[map[string]interface{}](client, spec, "unknown").Status(ctx)
+	var apiErr *aidge.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != aidge.KindInvalidParameter {
+		t.Errorf("Status of an unknown task: %v, want an invalid parameter", err)
+	}
+}
+
+func TestServerFailedTask(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualModelTask
+	srv.Task(spec, aidgetest.Running(), aidgetest.Failed("ImageInvalid", "no person in the image"))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+	ctx := context.Background()
+
+	task := aidge.NewTask[map[string]interface{}](client, spec)
+	if err := task.Submit(ctx, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := task.Wait(ctx)
+	var failed *aidge.TaskFailedError
+	if !errors.As(err, &failed) || failed.Code != "ImageInvalid" || failed.Message != "no person in the image" {
+		t.Errorf("Wait: %v, want the scripted failure", err)
+	}
+}
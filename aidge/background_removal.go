@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import "context"
+
+// BackgroundRemovalAPI is the Background Removal API.
+const BackgroundRemovalAPI = "/ai/image/cut/out"
+
+// BackgroundType is what replaces the removed background.
+type BackgroundType string
+
+const (
+	// WhiteBackground puts the subject on white, as listing images
+	// usually require.
+	WhiteBackground BackgroundType = "WHITE_BACKGROUND"
+
+	// TransparentBackground keeps only the subject, in a PNG with an alpha
+	// channel.
+	TransparentBackground BackgroundType = "TRANSPARENT_BACKGROUND"
+)
+
+// BackgroundTypes are the background types known to this package. The API
+// may support others, which CutOut sends as is.
+var BackgroundTypes = []BackgroundType{WhiteBackground, TransparentBackground}
+
+// CutOutRequest is a request to remove the background of an image.
+type CutOutRequest struct {
+	// Image is the image, given by URL or by content.
+	Image Image
+
+	// BackgroundType replaces the background, WhiteBackground when empty.
+	// Values other than the constants are sent to the API as is.
+	BackgroundType BackgroundType
+}
+
+// CutOutResult is the image without its background.
+type CutOutResult struct {
+	// ImageURL is the image with the background replaced.
+	ImageURL string `json:"imageUrl"`
+}
+
+// BackgroundRemovalService calls the Background Removal API.
+type BackgroundRemovalService struct {
+	client *Client
+}
+
+// CutOut removes the background of req.Image.
+func (s *BackgroundRemovalService) CutOut(ctx context.Context, req CutOutRequest) (*CutOutResult, error) {
+	if err := req.Image.validate(); err != nil {
+		return nil, err
+	}
+	backgroundType := req.BackgroundType
+	if backgroundType == "" {
+		backgroundType = WhiteBackground
+	}
+
+	request := struct {
+		ImageURL       string         `json:"imageUrl,omitempty"`
+		ImageBase64    string         `json:"imageBase64,omitempty"`
+		BackgroundType BackgroundType `json:"backGroundType"`
+	}{
+		ImageURL:       req.Image.URL,
+		ImageBase64:    req.Image.base64(),
+		BackgroundType: backgroundType,
+	}
+	var result CutOutResult
+	if err := s.client.Do(ctx, BackgroundRemovalAPI, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
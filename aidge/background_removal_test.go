@@ -0,0 +1,57 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestCutOut(t *testing.T) {
+	tests := []struct {
+		backgroundType aidge.BackgroundType
+		want           string
+	}{
+		{"", "WHITE_BACKGROUND"},
+		{aidge.TransparentBackground, "TRANSPARENT_BACKGROUND"},
+		// Values unknown to the package are left to the API to check.
+		{"GREY_BACKGROUND", "GREY_BACKGROUND"},
+	}
+	for _, tt := range tests {
+		srv := aidgetest.NewServer()
+		srv.Reply(aidge.BackgroundRemovalAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/cut.png"}))
+
+		result, err := srv.Client().BackgroundRemoval.CutOut(context.Background(), aidge.CutOutRequest{
+			Image:          aidge.ImageURL("https://example.com/a.jpg"),
+			BackgroundType: tt.backgroundType,
+		})
+		if err != nil {
+			t.Fatalf("CutOut(%q): %v", tt.backgroundType, err)
+		}
+		checkBody(t, sentBody(t, srv, aidge.BackgroundRemovalAPI), map[string]interface{}{
+			"imageUrl":       "https://example.com/a.jpg",
+			"backGroundType": tt.want,
+		})
+		if result.ImageURL != "https://example.com/cut.png" {
+			t.Errorf("CutOut(%q) = %+v", tt.backgroundType, result)
+		}
+		srv.Close()
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import "net/http"
+
+// ServiceInfo describes an Aidge API: where it is served, how it is called
+// and the parameters it takes.
+type ServiceInfo struct {
+	// Name is the short name of the API, e.g. "image-translation".
+	Name string `json:"name"`
+
+	// Title is the name of the API in the Aidge documentation.
+	Title string `json:"title"`
+
+	// Path is the apiName passed to Client.Do.
+	Path string `json:"path"`
+
+	// Method is the HTTP method of Path.
+	Method string `json:"method"`
+
+	// Async tells that Path submits a task whose result is queried from
+	// ResultPath with ResultMethod, passing the task id as IDField.
+	Async        bool   `json:"async"`
+	ResultPath   string `json:"resultPath,omitempty"`
+	ResultMethod string `json:"resultMethod,omitempty"`
+	IDField      string `json:"idField,omitempty"`
+
+	// Params are the parameters of the request body.
+	Params []Field `json:"params"`
+
+	// Result are the fields of the data of a successful response, or of the
+	// finished task for asynchronous APIs.
+	Result []Field `json:"result"`
+}
+
+// Field is a parameter of a request or a field of a result, as sent on the
+// wire.
+type Field struct {
+	Name string `json:"name"`
+
+	// Type is the JSON type of the field: "string", "integer", "number",
+	// "boolean", "array" or "object". Many APIs take numbers, flags and even
+	// lists as strings, which is what Type reports.
+	Type string `json:"type"`
+
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Task returns the TaskSpec of an asynchronous API, as currently set in the
+// variable of the package, and false for a synchronous one.
+func (s ServiceInfo) Task() (TaskSpec, bool) {
+	for _, spec := range taskSpecs() {
+		if spec.SubmitAPI == s.Path {
+			return spec, true
+		}
+	}
+	return TaskSpec{}, false
+}
+
+// taskSpecs returns the specs of the asynchronous APIs in the catalog.
+func taskSpecs() []TaskSpec {
+	return []TaskSpec{
+		VirtualTryOnProTask,
+		HandFootRepairTask,
+		ImageTranslationProTask,
+		VirtualModelTask,
+	}
+}
+
+// Services returns the description of every API known to the package, in a
+// stable order.
+func Services() []ServiceInfo {
+	services := make([]ServiceInfo, len(catalog))
+	for i, s := range catalog {
+		services[i] = s.clone()
+	}
+	return services
+}
+
+// LookupService returns the API with the given short name or path.
+func LookupService(name string) (ServiceInfo, bool) {
+	for _, s := range catalog {
+		if s.Name == name || s.Path == name {
+			return s.clone(), true
+		}
+	}
+	return ServiceInfo{}, false
+}
+
+// clone returns a copy of s that shares no slice with it, so that callers
+// cannot change the catalog.
+func (s ServiceInfo) clone() ServiceInfo {
+	s.Params = append([]Field(nil), s.Params...)
+	s.Result = append([]Field(nil), s.Result...)
+	return s
+}
+
+// asyncService describes an API served by spec.
+func asyncService(spec TaskSpec, info ServiceInfo) ServiceInfo {
+	info.Path = spec.SubmitAPI
+	info.Method = http.MethodPost
+	info.Async = true
+	info.ResultPath = spec.ResultAPI
+	info.ResultMethod = spec.ResultMethod
+	if info.ResultMethod == "" {
+		info.ResultMethod = http.MethodPost
+	}
+	info.IDField = spec.IDField
+	return info
+}
+
+// imageParams are the parameters of the APIs taking an image by URL or
+// inline.
+var imageParams = []Field{
+	{Name: "imageUrl", Type: "string", Description: "URL of the image, accessible from the public network"},
+	{Name: "imageBase64", Type: "string", Description: "base64 encoded image, instead of imageUrl"},
+}
+
+var catalog = []ServiceInfo{
+	{
+		Name:   "text-translation",
+		Title:  "Text Translation",
+		Path:   TextTranslationAPI,
+		Method: http.MethodPost,
+		Params: []Field{
+			{Name: "text", Type: "string", Required: true, Description: "JSON encoded array of the texts to translate"},
+			{Name: "sourceLanguage", Type: "string", Required: true, Description: "language code of the texts"},
+			{Name: "targetLanguage", Type: "string", Required: true, Description: "language code to translate to"},
+			{Name: "formatType", Type: "string", Description: `"text" or "html"`},
+		},
+		Result: []Field{
+			{Name: "translatedList", Type: "array", Description: "translated texts, in the order of the request"},
+		},
+	},
+	{
+		Name:   "image-translation",
+		Title:  "Image Translation",
+		Path:   ImageTranslationAPI,
+		Method: http.MethodPost,
+		Params: []Field{
+			{Name: "imageUrl", Type: "string", Required: true, Description: "URL of the image, accessible from the public network"},
+			{Name: "sourceLanguage", Type: "string", Required: true, Description: "language code of the text in the image"},
+			{Name: "targetLanguage", Type: "string", Required: true, Description: "language code to translate to"},
+			{Name: "translatingTextInTheProduct", Type: "string", Description: `"true" to translate the text on the product itself`},
+			{Name: "useImageEditor", Type: "string", Description: `"true" to return the editor info of the translated image`},
+		},
+		Result: []Field{
+			{Name: "imageUrl", Type: "string", Description: "URL of the translated image"},
+			{Name: "editorInfo", Type: "object", Description: "editor info, when useImageEditor is set"},
+		},
+	},
+	asyncService(ImageTranslationProTask, ServiceInfo{
+		Name:  "image-translation-pro",
+		Title: "Image Translation Pro",
+		Params: []Field{
+			{Name: "paramJson", Type: "string", Required: true, Description: "JSON encoded array of {imageUrl, sourceLanguage, targetLanguage}"},
+		},
+		Result: []Field{
+			{Name: "result", Type: "array", Description: "one {imageUrl, success, code, message} per image, in the order of the request"},
+		},
+	}),
+	asyncService(VirtualTryOnProTask, ServiceInfo{
+		Name:  "virtual-tryon",
+		Title: "Virtual Try-On Pro",
+		Params: []Field{
+			{Name: "requestParams", Type: "string", Required: true, Description: "JSON encoded array of {clothesList, model, viewType, inputQualityDetect, generateCount}"},
+		},
+		Result: []Field{
+			{Name: "result", Type: "array", Description: "generated images, {imageUrl}"},
+		},
+	}),
+	asyncService(VirtualModelTask, ServiceInfo{
+		Name:  "virtual-model",
+		Title: "Virtual Model Alternation",
+		Params: append(imageParams[:len(imageParams):len(imageParams)],
+			Field{Name: "maskKeepBg", Type: "string", Description: `"true" to keep the background of the image`},
+			Field{Name: "dimension", Type: "string", Description: "side of the generated images in pixels"},
+			Field{Name: "age", Type: "string", Description: "age group of the model, e.g. YOUTH"},
+			Field{Name: "bgStyle", Type: "string", Description: "scene behind the model, e.g. room"},
+			Field{Name: "model", Type: "string", Description: "appearance of the model, e.g. WHITE"},
+			Field{Name: "gender", Type: "string", Description: "gender of the model, e.g. FEMALE"},
+			Field{Name: "imageStyle", Type: "string", Description: "rendering style, e.g. realPhoto"},
+			Field{Name: "count", Type: "string", Description: "number of images to generate"},
+		),
+		Result: []Field{
+			{Name: "result", Type: "array", Description: "generated images, {imageUrl}"},
+		},
+	}),
+	asyncService(HandFootRepairTask, ServiceInfo{
+		Name:  "hand-foot-repair",
+		Title: "Hands and Feet Repair",
+		Params: []Field{
+			{Name: "paramJson", Type: "array", Required: true, Description: "array of {area, imageUrl, imgNum, requestBizId}"},
+		},
+		Result: []Field{
+			{Name: "result", Type: "array", Description: "one {requestBizId, imageUrls, success, code, message} per item"},
+		},
+	}),
+	{
+		Name:   "image-upscaling",
+		Title:  "Image Upscaling",
+		Path:   ImageUpscalingAPI,
+		Method: http.MethodPost,
+		Params: append(imageParams[:len(imageParams):len(imageParams)],
+			Field{Name: "upscaleFactor", Type: "integer", Required: true, Description: "2, 3 or 4"},
+		),
+		Result: []Field{
+			{Name: "imageUrl", Type: "string", Description: "URL of the upscaled image"},
+			{Name: "width", Type: "integer"},
+			{Name: "height", Type: "integer"},
+		},
+	},
+	{
+		Name:   "image-cropping",
+		Title:  "Image Cropping",
+		Path:   ImageCroppingAPI,
+		Method: http.MethodPost,
+		Params: append(imageParams[:len(imageParams):len(imageParams)],
+			Field{Name: "targetWidth", Type: "string", Required: true, Description: "width of the cropped image in pixels"},
+			Field{Name: "targetHeight", Type: "string", Required: true, Description: "height of the cropped image in pixels"},
+		),
+		Result: []Field{
+			{Name: "imageUrl", Type: "string", Description: "URL of the cropped image"},
+			{Name: "width", Type: "integer"},
+			{Name: "height", Type: "integer"},
+		},
+	},
+	{
+		Name:   "background-removal",
+		Title:  "Image Background Removal",
+		Path:   BackgroundRemovalAPI,
+		Method: http.MethodPost,
+		Params: append(imageParams[:len(imageParams):len(imageParams)],
+			Field{Name: "backGroundType", Type: "string", Description: "e.g. WHITE_BACKGROUND or TRANSPARENT_BACKGROUND"},
+		),
+		Result: []Field{
+			{Name: "imageUrl", Type: "string", Description: "URL of the image without background"},
+		},
+	},
+	{
+		Name:   "elements-removal",
+		Title:  "Image Elements Removal",
+		Path:   ElementsRemovalAPI,
+		Method: http.MethodPost,
+		Params: []Field{
+			{Name: "image_url", Type: "string", Required: true, Description: "URL of the image, accessible from the public network"},
+			{Name: "object_remove_elements", Type: "string", Description: "JSON encoded array of elements to erase from the product: 1 logo, 2 watermark, 3 text, 4 sticker"},
+			{Name: "non_object_remove_elements", Type: "string", Description: "JSON encoded array of elements to erase from the rest of the image"},
+		},
+		Result: []Field{
+			{Name: "image_url", Type: "string", Description: "URL of the image with the elements erased"},
+		},
+	},
+}
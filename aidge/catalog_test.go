@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
+
+func TestLookupService(t *testing.T) {
+	byName, ok := aidge.LookupService("background-removal")
+	if !ok || byName.Path != aidge.BackgroundRemovalAPI || byName.Async {
+		t.Errorf("LookupService(background-removal) = %+v, %v", byName, ok)
+	}
+	byPath, ok := aidge.LookupService(aidge.BackgroundRemovalAPI)
+	if !ok || byPath.Name != "background-removal" {
+		t.Errorf("LookupService(%s) = %+v, %v", aidge.BackgroundRemovalAPI, byPath, ok)
+	}
+	if _, ok := aidge.LookupService("/ai/unknown"); ok {
+		t.Error("LookupService found an unknown API")
+	}
+}
+
+func TestServicesDescribeTasks(t *testing.T) {
+	for _, s := range aidge.Services() {
+		spec, async := s.Task()
+		if async != s.Async {
+			t.Errorf("%s: Task() = %v, want %v", s.Name, async, s.Async)
+			continue
+		}
+		if !async {
+			if s.Method != http.MethodPost || s.ResultPath != "" {
+				t.Errorf("%s: synchronous API with method %s and result path %q", s.Name, s.Method, s.ResultPath)
+			}
+			continue
+		}
+		if spec.SubmitAPI != s.Path || spec.ResultAPI != s.ResultPath || spec.IDField != s.IDField {
+			t.Errorf("%s: spec %+v does not match %+v", s.Name, spec, s)
+		}
+	}
+}
+
+func TestServiceTaskIsCurrent(t *testing.T) {
+	saved := aidge.HandFootRepairTask
+	defer func() { aidge.HandFootRepairTask = saved }()
+	poll := aidge.FixedPoll(time.Millisecond)
+	aidge.HandFootRepairTask.Poll = poll
+
+	s, _ := aidge.LookupService("hand-foot-repair")
+	if spec, _ := s.Task(); spec.Poll != poll {
+		t.Errorf("Task().Poll = %v, want the strategy set on HandFootRepairTask", spec.Poll)
+	}
+}
+
+func TestServicesIsACopy(t *testing.T) {
+	services := aidge.Services()
+	services[0].Path = "/changed"
+	services[0].Params[0].Name = "changed"
+	services[0].Result[0].Name = "changed"
+	fresh := aidge.Services()[0]
+	if fresh.Path == "/changed" || fresh.Params[0].Name == "changed" || fresh.Result[0].Name == "changed" {
+		t.Errorf("Services returned the catalog itself: %+v", fresh)
+	}
+
+	service, _ := aidge.LookupService(fresh.Name)
+	service.Params[0].Name = "changed"
+	if service, _ := aidge.LookupService(fresh.Name); service.Params[0].Name == "changed" {
+		t.Error("LookupService returned the entry of the catalog itself")
+	}
+}
@@ -0,0 +1,327 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultApiDomain is the gateway for APIs purchased on the global site.
+	DefaultApiDomain = "api.aidc-ai.com"
+
+	// ChinaApiDomain is the gateway for APIs purchased on the Chinese site.
+	ChinaApiDomain = "cn-api.aidc-ai.com"
+
+	// DefaultTimeout bounds a single call when neither the context nor
+	// WithTimeout sets a tighter limit.
+	DefaultTimeout = 2 * time.Minute
+)
+
+// ApiConfig holds the credentials and gateway settings of a Client.
+type ApiConfig struct {
+	// AccessKeyName is the name of the API key, e.g. "512345".
+	AccessKeyName string
+
+	// AccessKeySecret is the secret of the API key.
+	AccessKeySecret string
+
+	// ApiDomain is the gateway host, DefaultApiDomain or ChinaApiDomain.
+	// A value with a scheme such as "http://127.0.0.1:8080" is used as the
+	// base URL as is.
+	ApiDomain string
+
+	// UseTrialResource sends the "x-iop-trial" header so that calls are
+	// charged against the trial quota of the account. Without it, calling an
+	// API that has not been purchased fails with "Sorry, your calling
+	// resources have been exhausted".
+	UseTrialResource bool
+}
+
+// Client calls Aidge APIs. It is safe for concurrent use.
+//
+// Besides Do, which calls any API, the typed services of the client encode
+// the requests and decode the results of each API.
+type Client struct {
+	// TextTranslation translates texts.
+	TextTranslation *TextTranslationService
+
+	// ImageTranslation translates the text in images.
+	ImageTranslation *ImageTranslationService
+
+	// ImageTranslationPro translates the text in batches of images.
+	ImageTranslationPro *ImageTranslationProService
+
+	// VirtualTryOn dresses models in clothes.
+	VirtualTryOn *VirtualTryOnService
+
+	// VirtualModel replaces the model wearing a garment.
+	VirtualModel *VirtualModelService
+
+	// HandFootRepair repairs the hands and feet of models.
+	HandFootRepair *HandFootRepairService
+
+	// ImageUpscaling upscales images.
+	ImageUpscaling *ImageUpscalingService
+
+	// ImageCropping crops images around their subject.
+	ImageCropping *ImageCroppingService
+
+	// BackgroundRemoval removes the background of images.
+	BackgroundRemoval *BackgroundRemovalService
+
+	// ElementsRemoval erases logos, watermarks and text from images.
+	ElementsRemoval *ElementsRemovalService
+
+	config     ApiConfig
+	signer     *Signer
+	httpClient *http.Client
+	timeout    time.Duration
+	retry      RetryPolicy
+	limits     limits
+	polls      polls
+	tasks      TaskStore
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to reach the gateway.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds each attempt of a call, including reading the response,
+// to d. An attempt that times out is retried like a network failure. The
+// deadline of the context passed to a call still applies when it is earlier.
+// Zero removes the limit and leaves it to the context.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithSigner sets the Signer used to sign requests, e.g. one with a fixed
+// clock. By default the client signs with the key of its ApiConfig.
+func WithSigner(signer *Signer) Option {
+	return func(c *Client) {
+		c.signer = signer
+	}
+}
+
+// NewClient returns a Client for the given configuration.
+func NewClient(config ApiConfig, opts ...Option) *Client {
+	c := &Client{
+		config:     config,
+		signer:     NewSigner(config.AccessKeyName, config.AccessKeySecret),
+		httpClient: http.DefaultClient,
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
+		limits:     limits{apis: map[string]*limiter{}},
+		polls:      polls{apis: map[string]PollStrategy{}},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.TextTranslation = &TextTranslationService{client: c}
+	c.ImageTranslation = &ImageTranslationService{client: c}
+	c.ImageTranslationPro = &ImageTranslationProService{client: c}
+	c.VirtualTryOn = &VirtualTryOnService{client: c}
+	c.VirtualModel = &VirtualModelService{client: c}
+	c.HandFootRepair = &HandFootRepairService{client: c}
+	c.ImageUpscaling = &ImageUpscalingService{client: c}
+	c.ImageCropping = &ImageCroppingService{client: c}
+	c.BackgroundRemoval = &BackgroundRemovalService{client: c}
+	c.ElementsRemoval = &ElementsRemovalService{client: c}
+	return c
+}
+
+// Config returns the configuration of the client.
+func (c *Client) Config() ApiConfig {
+	return c.config
+}
+
+// Do calls apiName, e.g. "/ai/text/marco/translator", with request as the
+// JSON body and decodes the data of the response envelope into response.
+//
+// request may be a string, []byte or json.RawMessage holding the JSON body, or
+// any other value which is encoded with json.Marshal. response may be a
+// *string, *[]byte or *json.RawMessage receiving the raw data, a *Response
+// receiving the whole envelope, any other pointer which is decoded with
+// json.Unmarshal, or nil to discard the data.
+//
+// A failure reported by the gateway or the API is returned as an *APIError.
+//
+// Transient failures are retried as set by WithRetry. The call is abandoned
+// with the context's error once ctx is done.
+func (c *Client) Do(ctx context.Context, apiName string, request, response interface{}, opts ...CallOption) error {
+	body, err := encodeRequest(request)
+	if err != nil {
+		return fmt.Errorf("aidge: %s: encoding request: %w", apiName, err)
+	}
+	return c.call(ctx, http.MethodPost, apiName, nil, body, response, opts)
+}
+
+// Get calls apiName with an HTTP GET, passing params in the query string, and
+// decodes the JSON response into response like Do. A few result endpoints,
+// such as "/ai/image/translation_mllm/results", are only served over GET.
+func (c *Client) Get(ctx context.Context, apiName string, params url.Values, response interface{}, opts ...CallOption) error {
+	return c.call(ctx, http.MethodGet, apiName, params, nil, response, opts)
+}
+
+// call sends a signed request and decodes the response, retrying transient
+// failures as allowed by the retry policy. Each attempt waits for the rate
+// limits of apiName. The request is abandoned as soon as ctx is done.
+func (c *Client) call(ctx context.Context, method, apiName string, params url.Values, body []byte, response interface{}, opts []CallOption) error {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		release, err := c.limits.acquire(ctx, apiName)
+		if err != nil {
+			return fmt.Errorf("aidge: %s: waiting for rate limit: %w", apiName, err)
+		}
+		hint, transient, err := c.send(ctx, method, apiName, params, body, response)
+		var apiErr *APIError
+		release(errors.As(err, &apiErr) && apiErr.Kind == KindThrottled)
+		if err == nil || !c.retry.shouldRetry(attempt, apiName, &o, err, transient) {
+			return err
+		}
+		pause, ok := c.retry.backoff().Next(PollState{
+			Attempt: attempt,
+			Elapsed: time.Since(start),
+			Hint:    hint,
+		})
+		if !ok || sleep(ctx, pause) != nil {
+			return err
+		}
+	}
+}
+
+// send makes a single attempt of a call. On failure it reports whether the
+// failure is transient, and the pause requested by the server. A failure is
+// not transient once parent is done, but the attempt timing out is.
+func (c *Client) send(parent context.Context, method, apiName string, params url.Values, body []byte, response interface{}) (hint time.Duration, transient bool, err error) {
+	ctx := parent
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, c.timeout)
+		defer cancel()
+	}
+
+	// Sign each attempt anew, the gateway rejects stale timestamps.
+	req, err := http.NewRequestWithContext(ctx, method, c.signer.SignURL(c.config.ApiDomain, apiName, params), bytes.NewReader(body))
+	if err != nil {
+		return 0, false, transportError(method, apiName, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Add "x-iop-trial": "true" for trial
+	if c.config.UseTrialResource {
+		req.Header.Set("x-iop-trial", "true")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, parent.Err() == nil, transportError(method, apiName, err)
+	}
+	defer resp.Body.Close()
+	hint = retryAfter(resp.Header)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return hint, parent.Err() == nil, fmt.Errorf("aidge: %s: reading response: %w", apiName, err)
+	}
+
+	var envelope Response
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			apiErr := httpError(apiName, resp.StatusCode, data)
+			return hint, apiErr.transient(), apiErr
+		}
+		return hint, false, fmt.Errorf("aidge: %s: decoding response: %w", apiName, err)
+	}
+	if err := envelope.err(apiName, resp.StatusCode); err != nil {
+		return hint, err.transient(), err
+	}
+	if r, ok := response.(*Response); ok {
+		envelope.Header = resp.Header
+		*r = envelope
+		return 0, false, nil
+	}
+	if err := decodeResponse(envelope.Data, response); err != nil {
+		return hint, false, fmt.Errorf("aidge: %s: decoding response data: %w", apiName, err)
+	}
+	return 0, false, nil
+}
+
+// transportError returns err, failing to send a request, without the signed
+// URL that *url.Error carries, as it holds the API key and signature.
+func transportError(method, apiName string, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return fmt.Errorf("aidge: %s %s: %w", method, apiName, err)
+}
+
+// encodeRequest returns the JSON body for request.
+func encodeRequest(request interface{}) ([]byte, error) {
+	switch r := request.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(r), nil
+	case []byte:
+		return r, nil
+	case json.RawMessage:
+		return r, nil
+	default:
+		return json.Marshal(request)
+	}
+}
+
+// decodeResponse stores the JSON body data into response.
+func decodeResponse(data []byte, response interface{}) error {
+	switch r := response.(type) {
+	case nil:
+		return nil
+	case *string:
+		*r = string(data)
+		return nil
+	case *[]byte:
+		*r = data
+		return nil
+	case *json.RawMessage:
+		*r = data
+		return nil
+	default:
+		return json.Unmarshal(data, response)
+	}
+}
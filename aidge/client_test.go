@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestTransportErrorHidesSignedURL(t *testing.T) {
+	srv := aidgetest.NewServer()
+	client := srv.Client(aidge.WithRetry(aidge.RetryPolicy{MaxAttempts: 1}))
+	srv.Close()
+
+	err := client.Do(context.Background(), aidge.BackgroundRemovalAPI, "{}", nil)
+	if err == nil {
+		t.Fatal("Do succeeded against a closed server")
+	}
+	for _, param := range []string{"sign=", "app_key=", "timestamp=", aidgetest.DefaultAccessKeyName} {
+		if strings.Contains(err.Error(), param) {
+			t.Errorf("error %q contains %q", err, param)
+		}
+	}
+	if !strings.Contains(err.Error(), aidge.BackgroundRemovalAPI) {
+		t.Errorf("error %q does not name the API", err)
+	}
+}
+
+func TestAttemptTimeoutIsRetried(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	ok := aidgetest.OK(map[string]string{"imageUrl": "https://example.com/out.png"})
+	slow := ok
+	slow.Delay = time.Second
+	srv.Reply(aidge.BackgroundRemovalAPI, slow, ok)
+	client := srv.Client(
+		aidge.WithTimeout(100*time.Millisecond),
+		aidge.WithRetry(aidge.RetryPolicy{MaxAttempts: 2, Backoff: aidge.FixedPoll(time.Millisecond)}))
+
+	if err := client.Do(context.Background(), aidge.BackgroundRemovalAPI, "{}", nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if n := len(srv.CallsTo(aidge.BackgroundRemovalAPI)); n != 2 {
+		t.Errorf("%d attempts, want 2", n)
+	}
+}
+
+func TestAttemptTimeoutOfTaskSubmitIsNotRetried(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.HandFootRepairTask.SubmitAPI, aidgetest.Reply{Delay: time.Second})
+	client := srv.Client(
+		aidge.WithTimeout(100*time.Millisecond),
+		aidge.WithRetry(aidge.RetryPolicy{MaxAttempts: 3, Backoff: aidge.FixedPoll(time.Millisecond)}))
+
+	err := client.Do(context.Background(), aidge.HandFootRepairTask.SubmitAPI, "{}", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do = %v, want a deadline exceeded error", err)
+	}
+	if n := len(srv.CallsTo(aidge.HandFootRepairTask.SubmitAPI)); n != 1 {
+		t.Errorf("%d attempts, want 1", n)
+	}
+}
+
+func TestCanceledCallIsNotRetried(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.BackgroundRemovalAPI, aidgetest.Reply{Delay: time.Second})
+	client := srv.Client(aidge.WithRetry(aidge.RetryPolicy{MaxAttempts: 3, Backoff: aidge.FixedPoll(time.Millisecond)}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := client.Do(ctx, aidge.BackgroundRemovalAPI, "{}", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do = %v, want a deadline exceeded error", err)
+	}
+	if n := len(srv.CallsTo(aidge.BackgroundRemovalAPI)); n != 1 {
+		t.Errorf("%d attempts, want 1", n)
+	}
+}
+
+// pausedPoll pauses like strategy and tells on paused when it does.
+type pausedPoll struct {
+	strategy aidge.PollStrategy
+	paused   chan<- struct{}
+}
+
+func (p pausedPoll) Next(state aidge.PollState) (time.Duration, bool) {
+	p.paused <- struct{}{}
+	return p.strategy.Next(state)
+}
+
+func TestWaitStopsWhenCanceledBetweenQueries(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.HandFootRepairTask
+	srv.Task(spec, aidgetest.Running())
+	paused := make(chan struct{}, 1)
+	client := srv.Client(aidge.WithPoll(pausedPoll{aidge.FixedPoll(time.Hour), paused}))
+
+	task := aidge.NewTask[struct{}](client, spec)
+	if err := task.Submit(context.Background(), map[string]string{}); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := task.Wait(ctx)
+		done <- err
+	}()
+
+	<-paused
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait still pausing a second after the cancellation")
+	}
+	if n := len(srv.CallsTo(spec.ResultAPI)); n != 1 {
+		t.Errorf("%d result queries, want 1", n)
+	}
+}
@@ -0,0 +1,30 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aidge is a client for the Aidge OpenAPI gateway.
+//
+// A Client signs every call with the sha256 (sign_ver=v2) scheme used by the
+// gateway and posts the JSON request to https://<ApiDomain>/rest<apiName>:
+//
+//	client := aidge.NewClient(aidge.ApiConfig{
+//		AccessKeyName:   os.Getenv("accessKey"),
+//		AccessKeySecret: os.Getenv("secret"),
+//		ApiDomain:       aidge.DefaultApiDomain,
+//	})
+//
+//	var result json.RawMessage
+//	err := client.Do(ctx, "/ai/text/marco/translator", request, &result)
+package aidge
@@ -0,0 +1,133 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ElementsRemovalAPI is the Image Elements Removal API.
+const ElementsRemovalAPI = "/ai/image/removal"
+
+// RemovableElement is a kind of element the Image Elements Removal API can
+// erase, numbered as the API expects it.
+type RemovableElement int
+
+const (
+	// ElementLogo is a brand logo.
+	ElementLogo RemovableElement = 1
+
+	// ElementWatermark is a watermark laid over the image.
+	ElementWatermark RemovableElement = 2
+
+	// ElementText is text, such as a slogan or a price.
+	ElementText RemovableElement = 3
+
+	// ElementSticker is a sticker or badge pasted on the image.
+	ElementSticker RemovableElement = 4
+)
+
+// AllElements are all the removable elements.
+var AllElements = []RemovableElement{ElementLogo, ElementWatermark, ElementText, ElementSticker}
+
+func (e RemovableElement) String() string {
+	switch e {
+	case ElementLogo:
+		return "logo"
+	case ElementWatermark:
+		return "watermark"
+	case ElementText:
+		return "text"
+	case ElementSticker:
+		return "sticker"
+	}
+	return fmt.Sprintf("RemovableElement(%d)", int(e))
+}
+
+// ElementsRemovalRequest is a request to erase elements from an image.
+type ElementsRemovalRequest struct {
+	// ImageURL is the image, accessible from the public network.
+	ImageURL string
+
+	// ObjectElements are erased from the product in the image.
+	ObjectElements []RemovableElement
+
+	// NonObjectElements are erased from the rest of the image.
+	NonObjectElements []RemovableElement
+}
+
+// ElementsRemovalResult is the image with the elements erased.
+type ElementsRemovalResult struct {
+	// ImageURL is the image with the elements erased.
+	ImageURL string `json:"image_url"`
+}
+
+// ElementsRemovalService calls the Image Elements Removal API.
+type ElementsRemovalService struct {
+	client *Client
+}
+
+// Remove erases the requested elements from req.ImageURL.
+func (s *ElementsRemovalService) Remove(ctx context.Context, req ElementsRemovalRequest) (*ElementsRemovalResult, error) {
+	if req.ImageURL == "" {
+		return nil, fmt.Errorf("%w: no image to remove elements from", ErrInvalidRequest)
+	}
+	if len(req.ObjectElements) == 0 && len(req.NonObjectElements) == 0 {
+		return nil, fmt.Errorf("%w: no elements to remove", ErrInvalidRequest)
+	}
+	objectElements, err := encodeElements(req.ObjectElements)
+	if err != nil {
+		return nil, err
+	}
+	nonObjectElements, err := encodeElements(req.NonObjectElements)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API takes the element lists as JSON encoded arrays.
+	request := map[string]string{
+		"image_url":                  req.ImageURL,
+		"object_remove_elements":     objectElements,
+		"non_object_remove_elements": nonObjectElements,
+	}
+	var result ElementsRemovalResult
+	if err := s.client.Do(ctx, ElementsRemovalAPI, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// encodeElements returns elements as a sorted JSON array without duplicates.
+func encodeElements(elements []RemovableElement) (string, error) {
+	seen := map[RemovableElement]bool{}
+	list := []int{}
+	for _, e := range elements {
+		if e < ElementLogo || e > ElementSticker {
+			return "", fmt.Errorf("%w: unknown element %d", ErrInvalidRequest, int(e))
+		}
+		if !seen[e] {
+			seen[e] = true
+			list = append(list, int(e))
+		}
+	}
+	sort.Ints(list)
+	encoded, err := json.Marshal(list)
+	return string(encoded), err
+}
@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestRemoveElements(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ElementsRemovalAPI, aidgetest.OK(map[string]string{"image_url": "https://example.com/clean.png"}))
+
+	result, err := srv.Client().ElementsRemoval.Remove(context.Background(), aidge.ElementsRemovalRequest{
+		ImageURL:          "https://example.com/a.jpg",
+		ObjectElements:    []aidge.RemovableElement{aidge.ElementText, aidge.ElementLogo, aidge.ElementText},
+		NonObjectElements: []aidge.RemovableElement{aidge.ElementWatermark},
+	})
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	// The API takes the element lists as JSON encoded arrays, in snake case.
+	checkBody(t, sentBody(t, srv, aidge.ElementsRemovalAPI), map[string]interface{}{
+		"image_url":                  "https://example.com/a.jpg",
+		"object_remove_elements":     "[1,3]",
+		"non_object_remove_elements": "[2]",
+	})
+	if result.ImageURL != "https://example.com/clean.png" {
+		t.Errorf("Remove = %+v", result)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRequest is returned, wrapped, when a typed request fails
+// validation before it is sent.
+var ErrInvalidRequest = errors.New("aidge: invalid request")
+
+// ErrorKind classifies the failures reported by the gateway.
+type ErrorKind int
+
+const (
+	// KindUnknown is a failure that matches none of the other kinds.
+	KindUnknown ErrorKind = iota
+
+	// KindAuth is a rejected API key or signature.
+	KindAuth
+
+	// KindQuotaExhausted means the calling resources of the API, purchased or
+	// trial, are used up.
+	KindQuotaExhausted
+
+	// KindInvalidParameter is a request the API refused to process.
+	KindInvalidParameter
+
+	// KindThrottled means the call rate limit of the key was exceeded.
+	KindThrottled
+
+	// KindServer is a failure on the gateway or service side.
+	KindServer
+)
+
+var kindNames = [...]string{
+	KindUnknown:          "unknown",
+	KindAuth:             "auth",
+	KindQuotaExhausted:   "quota_exhausted",
+	KindInvalidParameter: "invalid_parameter",
+	KindThrottled:        "throttled",
+	KindServer:           "server",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// APIError is a failure reported by the gateway or the API. Use errors.As to
+// inspect it:
+//
+//	var apiErr *aidge.APIError
+//	if errors.As(err, &apiErr) && apiErr.Kind == aidge.KindQuotaExhausted {
+//		// buy more resources or set UseTrialResource
+//	}
+type APIError struct {
+	// APIName is the API that failed, e.g. "/ai/image/cut/out".
+	APIName string
+
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+
+	// Code and Message are the error code and message of the response.
+	Code    string
+	Message string
+
+	// Type is the side the gateway blames, e.g. "ISV" for the caller and
+	// "ISP" for the service, when reported.
+	Type string
+
+	// RequestID identifies the call for Aidge support.
+	RequestID string
+
+	// Kind classifies Code.
+	Kind ErrorKind
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "aidge: %s: %s", e.APIName, e.Code)
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request_id %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// transient reports whether the failure may go away when the call is retried.
+func (e *APIError) transient() bool {
+	return e.Kind == KindServer || e.Kind == KindThrottled
+}
+
+// Error codes of the gateway, by kind.
+var errorCodeKinds = map[string]ErrorKind{
+	"IncompleteSignature":  KindAuth,
+	"InvalidSignature":     KindAuth,
+	"IllegalAccessToken":   KindAuth,
+	"InvalidAppKey":        KindAuth,
+	"AppKeyNotExists":      KindAuth,
+	"AccessDenied":         KindAuth,
+	"Unauthorized":         KindAuth,
+	"InsufficientResource": KindQuotaExhausted,
+	"ResourceExhausted":    KindQuotaExhausted,
+	"QuotaExhausted":       KindQuotaExhausted,
+	"MissingParameter":     KindInvalidParameter,
+	"InvalidParameter":     KindInvalidParameter,
+	"IllegalParameter":     KindInvalidParameter,
+	"InvalidRequest":       KindInvalidParameter,
+	"ApiCallLimit":         KindThrottled,
+	"AppCallLimit":         KindThrottled,
+	"Throttling":           KindThrottled,
+	"TooManyRequests":      KindThrottled,
+	"ServiceUnavailable":   KindServer,
+	"ServiceTimeout":       KindServer,
+	"InternalError":        KindServer,
+	"SystemError":          KindServer,
+}
+
+// classify returns the kind of the failure reported with the HTTP status,
+// error code, message and type.
+func classify(statusCode int, code, message, typ string) ErrorKind {
+	if kind, ok := errorCodeKinds[code]; ok {
+		return kind
+	}
+	// The exhausted message is reported under several codes.
+	if strings.Contains(strings.ToLower(message), "calling resources have been exhausted") {
+		return KindQuotaExhausted
+	}
+	// Some APIs report HTTP statuses as their code.
+	if n, err := strconv.Atoi(code); err == nil && n >= 400 && n <= 599 {
+		statusCode = n
+	}
+	switch {
+	case statusCode == http.StatusBadRequest:
+		return KindInvalidParameter
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return KindAuth
+	case statusCode == http.StatusTooManyRequests:
+		return KindThrottled
+	case statusCode >= 500:
+		return KindServer
+	case strings.HasPrefix(code, "Missing"), strings.HasPrefix(code, "Invalid"), strings.HasPrefix(code, "Illegal"):
+		return KindInvalidParameter
+	case strings.EqualFold(typ, "ISP"), strings.EqualFold(typ, "SYSTEM"):
+		return KindServer
+	}
+	return KindUnknown
+}
+
+// itemStatus is the outcome reported by a task for one item of a batch.
+type itemStatus struct {
+	Success *bool      `json:"success"`
+	Code    flexString `json:"code"`
+	Message string     `json:"message"`
+}
+
+// err returns the failure of item i, reported by apiName, when the item is
+// reported as failed or, with hasImage false, returned no image; nil
+// otherwise.
+func (s itemStatus) err(apiName string, i int, hasImage bool) error {
+	failed := s.Success != nil && !*s.Success
+	code := string(s.Code)
+	switch {
+	case failed && (code != "" || s.Message != ""):
+		return &APIError{
+			APIName: apiName,
+			Code:    code,
+			Message: s.Message,
+			Kind:    classify(0, code, s.Message, ""),
+		}
+	case failed:
+		return fmt.Errorf("aidge: %s: item %d failed with no error code", apiName, i)
+	case !hasImage:
+		return fmt.Errorf("aidge: %s: no image returned for item %d", apiName, i)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		status  int
+		code    string
+		message string
+		typ     string
+		want    ErrorKind
+	}{
+		{200, "InvalidSignature", "", "", KindAuth},
+		{200, "IllegalAccessToken", "", "", KindAuth},
+		{200, "InsufficientResource", "", "", KindQuotaExhausted},
+		{200, "SomeCode", "Sorry, your calling resources have been exhausted", "", KindQuotaExhausted},
+		{200, "MissingParameter", "", "", KindInvalidParameter},
+		{200, "InvalidImageFormat", "", "ISV", KindInvalidParameter},
+		{200, "ApiCallLimit", "", "", KindThrottled},
+		{200, "InternalError", "", "", KindServer},
+		{200, "Unexpected", "", "ISP", KindServer},
+		{200, "Unexpected", "", "", KindUnknown},
+
+		// The HTTP status decides when the code is not known.
+		{http.StatusBadRequest, "Unexpected", "", "", KindInvalidParameter},
+		{http.StatusForbidden, "Unexpected", "", "", KindAuth},
+		{http.StatusTooManyRequests, "Unexpected", "", "", KindThrottled},
+		{http.StatusBadGateway, "Unexpected", "", "", KindServer},
+
+		// Some APIs report HTTP statuses as their code.
+		{200, "429", "", "", KindThrottled},
+		{200, "503", "", "", KindServer},
+		{200, "401", "", "", KindAuth},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.status, tt.code, tt.message, tt.typ); got != tt.want {
+			t.Errorf("classify(%d, %q, %q, %q) = %v, want %v", tt.status, tt.code, tt.message, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestResponseErr(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		wantCode string
+		wantKind ErrorKind
+	}{
+		{"success", `{"code":"0","data":{"imageUrl":"x"}}`, 200, "", 0},
+		{"numeric success", `{"code":0,"data":{}}`, 200, "", 0},
+		{"gateway error", `{"code":"InvalidSignature","message":"bad signature","type":"ISV"}`, 200, "InvalidSignature", KindAuth},
+		{"status fallback", `{"code":"0"}`, http.StatusServiceUnavailable, "503", KindServer},
+		{"status fallback without code", `{}`, http.StatusTooManyRequests, "429", KindThrottled},
+		{"data failure", `{"code":"0","data":{"success":false,"code":"InvalidParameter","message":"bad image"}}`, 200, "InvalidParameter", KindInvalidParameter},
+		{"data success", `{"code":"0","data":{"success":true}}`, 200, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Response
+			if err := json.Unmarshal([]byte(tt.body), &r); err != nil {
+				t.Fatal(err)
+			}
+			apiErr := r.err("/ai/test", tt.status)
+			if tt.wantCode == "" {
+				if apiErr != nil {
+					t.Errorf("err = %v, want nil", apiErr)
+				}
+				return
+			}
+			if apiErr == nil {
+				t.Fatalf("err = nil, want code %s", tt.wantCode)
+			}
+			if apiErr.Code != tt.wantCode || apiErr.Kind != tt.wantKind || apiErr.StatusCode != tt.status || apiErr.APIName != "/ai/test" {
+				t.Errorf("err = %+v, want code %s of kind %v", apiErr, tt.wantCode, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	apiErr := httpError("/ai/test", http.StatusBadGateway, []byte("<html>bad gateway</html>"))
+	if apiErr.Code != "502" || apiErr.Kind != KindServer || apiErr.Message != "<html>bad gateway</html>" || !apiErr.transient() {
+		t.Errorf("httpError = %+v", apiErr)
+	}
+	if apiErr := httpError("/ai/test", http.StatusNotFound, nil); apiErr.Message != "Not Found" || apiErr.transient() {
+		t.Errorf("httpError without body = %+v", apiErr)
+	}
+}
+
+func TestErrorsMatchWrapped(t *testing.T) {
+	throttled := &APIError{APIName: "/ai/test", Code: "Throttling", Kind: KindThrottled}
+	err := fmt.Errorf("translating chunk: %w", throttled)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != KindThrottled {
+		t.Errorf("errors.As(%v) = %v, want the throttled APIError", err, apiErr)
+	}
+	if !apiErr.transient() {
+		t.Error("throttled error is not transient")
+	}
+
+	invalid := fmt.Errorf("%w: no texts", ErrInvalidRequest)
+	if !errors.Is(fmt.Errorf("batch: %w", invalid), ErrInvalidRequest) {
+		t.Error("wrapped validation failure does not match ErrInvalidRequest")
+	}
+	var notAPI *APIError
+	if errors.As(invalid, &notAPI) {
+		t.Error("validation failure matches APIError")
+	}
+
+	for kind, want := range map[ErrorKind]string{KindQuotaExhausted: "quota_exhausted", KindServer: "server", ErrorKind(42): "ErrorKind(42)"} {
+		if got := kind.String(); got != want {
+			t.Errorf("ErrorKind(%d).String() = %q, want %q", int(kind), got, want)
+		}
+	}
+}
+
+func TestErrorKindDecisions(t *testing.T) {
+	tests := []struct {
+		kind      ErrorKind
+		transient bool
+		gone      bool
+	}{
+		{KindUnknown, false, true},
+		{KindAuth, false, false},
+		{KindQuotaExhausted, false, false},
+		{KindInvalidParameter, false, true},
+		{KindThrottled, true, false},
+		{KindServer, true, false},
+	}
+	for _, tt := range tests {
+		err := &APIError{Kind: tt.kind}
+		if got := err.transient(); got != tt.transient {
+			t.Errorf("%v: transient = %v, want %v", tt.kind, got, tt.transient)
+		}
+		if got := taskGone(err); got != tt.gone {
+			t.Errorf("%v: taskGone = %v, want %v", tt.kind, got, tt.gone)
+		}
+	}
+}
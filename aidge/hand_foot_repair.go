@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// RepairArea is the body part to repair.
+type RepairArea string
+
+const (
+	// AreaHand repairs the hands of the model.
+	AreaHand RepairArea = "hand"
+
+	// AreaFoot repairs the feet of the model.
+	AreaFoot RepairArea = "foot"
+)
+
+// RepairItem is an image to repair.
+type RepairItem struct {
+	// Area is the body part to repair.
+	Area RepairArea
+
+	// ImageURL is the image, accessible from the public network.
+	ImageURL string
+
+	// ImageCount is the number of repaired images to generate, 1 when zero.
+	ImageCount int
+
+	// RequestBizID is a business id of the caller for the item. When every
+	// item of a batch has one, the gateway drops duplicate submits, so the
+	// submit is retried like an idempotent call.
+	RequestBizID string
+}
+
+// RepairResult is the outcome for one item of a batch.
+type RepairResult struct {
+	// Item is the item of the batch.
+	Item RepairItem
+
+	// ImageURLs are the repaired images, empty when Err is set.
+	ImageURLs []string
+
+	// Err is the failure reported for the item.
+	Err error
+}
+
+// RepairBatch is a submitted batch.
+type RepairBatch struct {
+	// Items are the items of the batch, in the order they were submitted.
+	Items []RepairItem
+
+	task *Task[handFootRepairData]
+}
+
+// TaskID returns the id of the task repairing the batch.
+func (b *RepairBatch) TaskID() string {
+	return b.task.ID()
+}
+
+type handFootRepairData struct {
+	Result []struct {
+		RequestBizID string   `json:"requestBizId"`
+		ImageURLs    []string `json:"imageUrls"`
+		itemStatus
+	} `json:"result"`
+}
+
+// HandFootRepairService calls the Hands and Feet Repair API.
+type HandFootRepairService struct {
+	client *Client
+}
+
+// Submit submits items for repair. The submit is retried when every item
+// has a RequestBizID, or when opts include Idempotent.
+func (s *HandFootRepairService) Submit(ctx context.Context, items []RepairItem, opts ...CallOption) (*RepairBatch, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no images to repair", ErrInvalidRequest)
+	}
+	bizIDs := map[string]bool{}
+	params := make([]map[string]string, len(items))
+	for i, item := range items {
+		switch {
+		case item.Area != AreaHand && item.Area != AreaFoot:
+			return nil, fmt.Errorf("%w: item %d has unknown area %q", ErrInvalidRequest, i, item.Area)
+		case item.ImageURL == "":
+			return nil, fmt.Errorf("%w: item %d has no image", ErrInvalidRequest, i)
+		case item.ImageCount < 0:
+			return nil, fmt.Errorf("%w: item %d has a negative image count", ErrInvalidRequest, i)
+		case item.RequestBizID != "" && bizIDs[item.RequestBizID]:
+			return nil, fmt.Errorf("%w: request biz id %q is used twice", ErrInvalidRequest, item.RequestBizID)
+		}
+		if item.RequestBizID != "" {
+			bizIDs[item.RequestBizID] = true
+		}
+		params[i] = map[string]string{
+			"area":         string(item.Area),
+			"imageUrl":     item.ImageURL,
+			"imgNum":       strconv.Itoa(max(item.ImageCount, 1)),
+			"requestBizId": item.RequestBizID,
+		}
+	}
+	if len(bizIDs) == len(items) {
+		opts = append(opts[:len(opts):len(opts)], Idempotent())
+	}
+
+	task := NewTask[handFootRepairData](s.client, HandFootRepairTask)
+	err := task.Submit(ctx, map[string]interface{}{"paramJson": params}, opts...)
+	return submitted(err, func() *RepairBatch {
+		return &RepairBatch{Items: items, task: task}
+	})
+}
+
+// Attach returns the batch of items already submitted as task taskID.
+func (s *HandFootRepairService) Attach(taskID string, items []RepairItem) *RepairBatch {
+	return &RepairBatch{
+		Items: items,
+		task:  AttachTask[handFootRepairData](s.client, HandFootRepairTask, taskID),
+	}
+}
+
+// Wait waits for batch to finish and returns one result per item, in the
+// order of batch.Items. A failed item is reported in its Err.
+func (s *HandFootRepairService) Wait(ctx context.Context, batch *RepairBatch) ([]RepairResult, error) {
+	data, err := batch.task.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resultAPI := HandFootRepairTask.ResultAPI
+
+	// Match the results by business id when they carry one, by position when
+	// none does. Positions are not trusted once any result is tagged, so that
+	// a result is never given to two items.
+	byBizID := map[string]int{}
+	for i, r := range data.Result {
+		if r.RequestBizID != "" {
+			byBizID[r.RequestBizID] = i
+		}
+	}
+	results := make([]RepairResult, len(batch.Items))
+	for i, item := range batch.Items {
+		results[i].Item = item
+		j, ok := byBizID[item.RequestBizID]
+		if !ok && len(byBizID) == 0 && i < len(data.Result) {
+			j, ok = i, true
+		}
+		if !ok {
+			results[i].Err = fmt.Errorf("aidge: %s: no result for item %d", resultAPI, i)
+			continue
+		}
+		r := data.Result[j]
+		if err := r.err(resultAPI, i, len(r.ImageURLs) > 0); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].ImageURLs = r.ImageURLs
+	}
+	return results, nil
+}
+
+// Repair submits items, waits for the task to finish and returns one result
+// per item, in the order of items.
+func (s *HandFootRepairService) Repair(ctx context.Context, items []RepairItem, opts ...CallOption) ([]RepairResult, error) {
+	batch, err := s.Submit(ctx, items, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s.Wait(ctx, batch)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestRepairReportsItemFailures(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Task(aidge.HandFootRepairTask, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{
+			{"requestBizId": "b", "success": false, "code": "InvalidParameter", "message": "no hand found"},
+			{"requestBizId": "a", "success": true, "imageUrls": []string{"https://example.com/a.png"}},
+		},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	results, err := client.HandFootRepair.Repair(context.Background(), []aidge.RepairItem{
+		{Area: aidge.AreaHand, ImageURL: "https://example.com/a.jpg", RequestBizID: "a"},
+		{Area: aidge.AreaHand, ImageURL: "https://example.com/b.jpg", RequestBizID: "b"},
+	})
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if results[0].Err != nil || len(results[0].ImageURLs) != 1 {
+		t.Errorf("result a = %+v, want one image", results[0])
+	}
+	var apiErr *aidge.APIError
+	if !errors.As(results[1].Err, &apiErr) || apiErr.Kind != aidge.KindInvalidParameter || apiErr.Message != "no hand found" {
+		t.Errorf("result b: Err = %v, want the invalid parameter error of the item", results[1].Err)
+	}
+}
+
+func TestRepairMatchesUntaggedResultsByPosition(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Task(aidge.HandFootRepairTask, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{
+			{"imageUrls": []string{"https://example.com/a.png"}},
+			{"success": true},
+		},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	results, err := client.HandFootRepair.Repair(context.Background(), []aidge.RepairItem{
+		{Area: aidge.AreaHand, ImageURL: "https://example.com/a.jpg", RequestBizID: "a"},
+		{Area: aidge.AreaFoot, ImageURL: "https://example.com/b.jpg", RequestBizID: "b"},
+	})
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if results[0].Err != nil || len(results[0].ImageURLs) != 1 {
+		t.Errorf("result a = %+v, want the image of the first result", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "no image returned") {
+		t.Errorf("result b: Err = %v, want no image returned", results[1].Err)
+	}
+}
+
+func TestRepairDoesNotReuseTaggedResults(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Task(aidge.HandFootRepairTask, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{
+			{"imageUrls": []string{"https://example.com/untagged.png"}},
+			{"requestBizId": "x", "imageUrls": []string{"https://example.com/x.png"}},
+		},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	results, err := client.HandFootRepair.Repair(context.Background(), []aidge.RepairItem{
+		{Area: aidge.AreaHand, ImageURL: "https://example.com/a.jpg", RequestBizID: "x"},
+		{Area: aidge.AreaHand, ImageURL: "https://example.com/b.jpg"},
+	})
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if results[0].Err != nil || len(results[0].ImageURLs) != 1 || results[0].ImageURLs[0] != "https://example.com/x.png" {
+		t.Errorf("result a = %+v, want the result tagged x", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "no result for item 1") {
+		t.Errorf("result b = %+v, want no result", results[1])
+	}
+}
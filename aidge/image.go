@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// Image is an input image, given either by URL or by content.
+type Image struct {
+	// URL is an image accessible from the public network.
+	URL string
+
+	// Data is the content of a local image.
+	Data []byte
+}
+
+// ImageURL returns the image at url.
+func ImageURL(url string) Image {
+	return Image{URL: url}
+}
+
+// ImageBytes returns the image with content data.
+func ImageBytes(data []byte) Image {
+	return Image{Data: data}
+}
+
+// ImageReader returns the image read from r.
+func ImageReader(r io.Reader) (Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Image{}, fmt.Errorf("aidge: reading image: %w", err)
+	}
+	return Image{Data: data}, nil
+}
+
+// validate checks that exactly one of URL and Data is set.
+func (i Image) validate() error {
+	switch {
+	case i.URL == "" && len(i.Data) == 0:
+		return fmt.Errorf("%w: an image URL or image data is required", ErrInvalidRequest)
+	case i.URL != "" && len(i.Data) > 0:
+		return fmt.Errorf("%w: both an image URL and image data are set", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// base64 returns Data encoded as the imageBase64 parameters expect it.
+func (i Image) base64() string {
+	if len(i.Data) == 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(i.Data)
+}
+
+// size returns the width and height of a local image, or false when the
+// image is given by URL or its format is not known.
+func (i Image) size() (width, height int, ok bool) {
+	if len(i.Data) == 0 {
+		return 0, 0, false
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(i.Data))
+	if err != nil {
+		return 0, 0, false
+	}
+	return config.Width, config.Height, true
+}
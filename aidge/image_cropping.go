@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ImageCroppingAPI is the Image Cropping API.
+const ImageCroppingAPI = "/ai/image/cropping"
+
+// CropRequest is a request to crop an image to a target size around its
+// subject.
+type CropRequest struct {
+	// Image is the image to crop, given by URL or by content, e.g. with
+	// ImageBytes or ImageReader. Exactly one of them must be set.
+	Image Image
+
+	// TargetWidth and TargetHeight are the size in pixels of the cropped
+	// image.
+	TargetWidth  int
+	TargetHeight int
+}
+
+// CropResult is the cropped image.
+type CropResult struct {
+	// ImageURL is the cropped image.
+	ImageURL string `json:"imageUrl"`
+
+	// Width and Height are the size of the cropped image.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ImageCroppingService calls the Image Cropping API.
+type ImageCroppingService struct {
+	client *Client
+}
+
+// Crop crops req.Image to the target size.
+func (s *ImageCroppingService) Crop(ctx context.Context, req CropRequest) (*CropResult, error) {
+	if err := req.Image.validate(); err != nil {
+		return nil, err
+	}
+	if req.TargetWidth <= 0 || req.TargetHeight <= 0 {
+		return nil, fmt.Errorf("%w: target size %dx%d is not positive", ErrInvalidRequest, req.TargetWidth, req.TargetHeight)
+	}
+
+	// The API takes the target size as strings.
+	request := map[string]string{
+		"imageUrl":     req.Image.URL,
+		"imageBase64":  req.Image.base64(),
+		"targetWidth":  strconv.Itoa(req.TargetWidth),
+		"targetHeight": strconv.Itoa(req.TargetHeight),
+	}
+	var result CropResult
+	if err := s.client.Do(ctx, ImageCroppingAPI, request, &result); err != nil {
+		return nil, err
+	}
+	if result.Width == 0 {
+		result.Width, result.Height = req.TargetWidth, req.TargetHeight
+	}
+	return &result, nil
+}
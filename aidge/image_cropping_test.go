@@ -0,0 +1,51 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestCrop(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageCroppingAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/cropped.png"}))
+
+	result, err := srv.Client().ImageCropping.Crop(context.Background(), aidge.CropRequest{
+		Image:        aidge.ImageURL("https://example.com/a.jpg"),
+		TargetWidth:  800,
+		TargetHeight: 600,
+	})
+	if err != nil {
+		t.Fatalf("Crop: %v", err)
+	}
+	// The API takes the target size as strings.
+	checkBody(t, sentBody(t, srv, aidge.ImageCroppingAPI), map[string]interface{}{
+		"imageUrl":     "https://example.com/a.jpg",
+		"imageBase64":  "",
+		"targetWidth":  "800",
+		"targetHeight": "600",
+	})
+	want := aidge.CropResult{ImageURL: "https://example.com/cropped.png", Width: 800, Height: 600}
+	if *result != want {
+		t.Errorf("Crop = %+v, want %+v", *result, want)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ImageTranslationAPI is the Image Translation API.
+const ImageTranslationAPI = "/ai/image/translation"
+
+// ImageTranslationService calls the Image Translation API.
+type ImageTranslationService struct {
+	client *Client
+}
+
+// ImageTranslateRequest is a request to translate the text in an image.
+type ImageTranslateRequest struct {
+	// ImageURL is the image, accessible from the public network.
+	ImageURL string `json:"imageUrl"`
+
+	// SourceLanguage is the language of the text in the image.
+	SourceLanguage Language `json:"sourceLanguage"`
+
+	// TargetLanguage is the language to translate to.
+	TargetLanguage Language `json:"targetLanguage"`
+
+	// TranslatingTextInTheProduct also translates the text printed on the
+	// product itself, not only the text around it.
+	TranslatingTextInTheProduct bool `json:"translatingTextInTheProduct,string"`
+
+	// UseImageEditor returns the layers of the translated image so that it
+	// can be adjusted in the Aidge image editor.
+	UseImageEditor bool `json:"useImageEditor,string"`
+}
+
+// ImageTranslateResult is the translated image.
+type ImageTranslateResult struct {
+	// ImageURL is the translated image.
+	ImageURL string `json:"imageUrl"`
+
+	// EditorInfo is the image editor payload, present when UseImageEditor
+	// was set.
+	EditorInfo json.RawMessage `json:"editorInfo,omitempty"`
+}
+
+// Translate translates the text in req.ImageURL.
+func (s *ImageTranslationService) Translate(ctx context.Context, req ImageTranslateRequest) (*ImageTranslateResult, error) {
+	if req.ImageURL == "" {
+		return nil, fmt.Errorf("%w: no image to translate", ErrInvalidRequest)
+	}
+	if req.SourceLanguage == "" || req.TargetLanguage == "" {
+		return nil, fmt.Errorf("%w: source and target languages are required", ErrInvalidRequest)
+	}
+
+	var result ImageTranslateResult
+	if err := s.client.Do(ctx, ImageTranslationAPI, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
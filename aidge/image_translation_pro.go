@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ImageTranslationProService calls the Image Translation Pro batch API.
+type ImageTranslationProService struct {
+	client *Client
+}
+
+// ImageTranslationProItem is an image of a batch and the languages to
+// translate it between.
+type ImageTranslationProItem struct {
+	// ImageURL is the image, accessible from the public network.
+	ImageURL string `json:"imageUrl"`
+
+	// SourceLanguage is the language of the text in the image.
+	SourceLanguage Language `json:"sourceLanguage"`
+
+	// TargetLanguage is the language to translate to.
+	TargetLanguage Language `json:"targetLanguage"`
+}
+
+// ImageTranslationProResult is the outcome for one item of a batch.
+type ImageTranslationProResult struct {
+	// Item is the item of the batch.
+	Item ImageTranslationProItem
+
+	// ImageURL is the translated image, empty when Err is set.
+	ImageURL string
+
+	// Err is the failure reported for the item.
+	Err error
+}
+
+// ImageTranslationProBatch is a submitted batch.
+type ImageTranslationProBatch struct {
+	// Items are the items of the batch, in the order they were submitted.
+	Items []ImageTranslationProItem
+
+	task *Task[imageTranslationProData]
+}
+
+// TaskID returns the id of the task translating the batch.
+func (b *ImageTranslationProBatch) TaskID() string {
+	return b.task.ID()
+}
+
+type imageTranslationProData struct {
+	Result []struct {
+		ImageURL string `json:"imageUrl"`
+		itemStatus
+	} `json:"result"`
+}
+
+// SubmitBatch submits items for translation. The submit is only retried
+// when opts include Idempotent.
+func (s *ImageTranslationProService) SubmitBatch(ctx context.Context, items []ImageTranslationProItem, opts ...CallOption) (*ImageTranslationProBatch, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no images to translate", ErrInvalidRequest)
+	}
+	for i, item := range items {
+		if item.ImageURL == "" || item.SourceLanguage == "" || item.TargetLanguage == "" {
+			return nil, fmt.Errorf("%w: item %d needs an image and source and target languages", ErrInvalidRequest, i)
+		}
+	}
+
+	// The API takes the items as a JSON encoded array inside the JSON body.
+	paramJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("aidge: %s: encoding items: %w", ImageTranslationProTask.SubmitAPI, err)
+	}
+	task := NewTask[imageTranslationProData](s.client, ImageTranslationProTask)
+	err = task.Submit(ctx, map[string]string{"paramJson": string(paramJSON)}, opts...)
+	return submitted(err, func() *ImageTranslationProBatch {
+		return &ImageTranslationProBatch{Items: items, task: task}
+	})
+}
+
+// AttachBatch returns the batch of items already submitted as task taskID.
+func (s *ImageTranslationProService) AttachBatch(taskID string, items []ImageTranslationProItem) *ImageTranslationProBatch {
+	return &ImageTranslationProBatch{
+		Items: items,
+		task:  AttachTask[imageTranslationProData](s.client, ImageTranslationProTask, taskID),
+	}
+}
+
+// WaitBatch waits for batch to finish and returns one result per item, in the
+// order of batch.Items. A failed item is reported in its Err.
+func (s *ImageTranslationProService) WaitBatch(ctx context.Context, batch *ImageTranslationProBatch) ([]ImageTranslationProResult, error) {
+	data, err := batch.task.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resultAPI := ImageTranslationProTask.ResultAPI
+	if len(data.Result) != len(batch.Items) {
+		return nil, fmt.Errorf("aidge: %s: got %d results for %d images", resultAPI, len(data.Result), len(batch.Items))
+	}
+
+	results := make([]ImageTranslationProResult, len(batch.Items))
+	for i, item := range batch.Items {
+		r := data.Result[i]
+		results[i] = ImageTranslationProResult{Item: item, ImageURL: r.ImageURL}
+		if err := r.err(resultAPI, i, r.ImageURL != ""); err != nil {
+			results[i].Err = err
+			results[i].ImageURL = ""
+		}
+	}
+	return results, nil
+}
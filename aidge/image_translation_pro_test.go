@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+var proItems = []aidge.ImageTranslationProItem{
+	{ImageURL: "https://example.com/a.jpg", SourceLanguage: aidge.ChineseSimplified, TargetLanguage: aidge.English},
+	{ImageURL: "https://example.com/b.jpg", SourceLanguage: aidge.ChineseSimplified, TargetLanguage: aidge.French},
+}
+
+func TestTranslateBatchOfImages(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.ImageTranslationProTask
+	srv.Task(spec, aidgetest.Running(), aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{
+			{"imageUrl": "https://example.com/a-en.jpg", "success": true},
+			{"success": false, "code": "InvalidParameter", "message": "no text found"},
+		},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	batch, err := client.ImageTranslationPro.SubmitBatch(context.Background(), proItems)
+	if err != nil {
+		t.Fatalf("SubmitBatch: %v", err)
+	}
+	results, err := client.ImageTranslationPro.WaitBatch(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("WaitBatch: %v", err)
+	}
+
+	// The API takes the items as a JSON encoded array in paramJson.
+	body := sentBody(t, srv, spec.SubmitAPI)
+	encoded, ok := body["paramJson"].(string)
+	if !ok || len(body) != 1 {
+		t.Fatalf("body = %v, want only the paramJson string", body)
+	}
+	var params []interface{}
+	if err := json.Unmarshal([]byte(encoded), &params); err != nil {
+		t.Fatalf("paramJson %q: %v", encoded, err)
+	}
+	var want []interface{}
+	json.Unmarshal([]byte(`[
+		{"imageUrl": "https://example.com/a.jpg", "sourceLanguage": "zh", "targetLanguage": "en"},
+		{"imageUrl": "https://example.com/b.jpg", "sourceLanguage": "zh", "targetLanguage": "fr"}
+	]`), &want)
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("paramJson = %s, want %v", encoded, want)
+	}
+
+	queries := srv.CallsTo(spec.ResultAPI)
+	if len(queries) != 2 {
+		t.Fatalf("%d result queries, want 2", len(queries))
+	}
+	if q := queries[0]; q.Method != http.MethodGet || q.Query.Get("taskId") != batch.TaskID() {
+		t.Errorf("result query %s with taskId %q, want GET with %q", q.Method, q.Query.Get("taskId"), batch.TaskID())
+	}
+
+	if r := results[0]; r.Item != proItems[0] || r.ImageURL != "https://example.com/a-en.jpg" || r.Err != nil {
+		t.Errorf("result 0 = %+v, want the translated image", r)
+	}
+	var apiErr *aidge.APIError
+	if r := results[1]; r.Item != proItems[1] || r.ImageURL != "" || !errors.As(r.Err, &apiErr) || apiErr.Message != "no text found" {
+		t.Errorf("result 1 = %+v, want the failure of the item", r)
+	}
+}
+
+func TestWaitBatchRejectsMissingResults(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Task(aidge.ImageTranslationProTask, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{
+			{"imageUrl": "https://example.com/a-en.jpg", "success": true},
+		},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	batch, err := client.ImageTranslationPro.SubmitBatch(context.Background(), proItems)
+	if err != nil {
+		t.Fatalf("SubmitBatch: %v", err)
+	}
+	results, err := client.ImageTranslationPro.WaitBatch(context.Background(), batch)
+	if err == nil || !strings.Contains(err.Error(), "got 1 results for 2 images") {
+		t.Errorf("WaitBatch = %+v, %v, want the result count mismatch", results, err)
+	}
+}
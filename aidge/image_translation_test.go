@@ -0,0 +1,55 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestTranslateImage(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageTranslationAPI, aidgetest.OK(map[string]interface{}{
+		"imageUrl":   "https://example.com/fr.png",
+		"editorInfo": map[string]string{"layers": "[]"},
+	}))
+
+	result, err := srv.Client().ImageTranslation.Translate(context.Background(), aidge.ImageTranslateRequest{
+		ImageURL:                    "https://example.com/en.jpg",
+		SourceLanguage:              aidge.English,
+		TargetLanguage:              aidge.French,
+		TranslatingTextInTheProduct: true,
+	})
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	// The API takes its flags as strings.
+	checkBody(t, sentBody(t, srv, aidge.ImageTranslationAPI), map[string]interface{}{
+		"imageUrl":                    "https://example.com/en.jpg",
+		"sourceLanguage":              "en",
+		"targetLanguage":              "fr",
+		"translatingTextInTheProduct": "true",
+		"useImageEditor":              "false",
+	})
+	if result.ImageURL != "https://example.com/fr.png" || string(result.EditorInfo) != `{"layers":"[]"}` {
+		t.Errorf("Translate = %+v", result)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"fmt"
+)
+
+// ImageUpscalingAPI is the Image Upscaling API.
+const ImageUpscalingAPI = "/ai/super/resolution"
+
+// upscaleFactors are the factors supported by the Image Upscaling API.
+var upscaleFactors = []int{2, 3, 4}
+
+// UpscaleFactors returns the factors supported by the Image Upscaling API.
+func UpscaleFactors() []int {
+	return append([]int(nil), upscaleFactors...)
+}
+
+// UpscaleRequest is a request to upscale an image.
+type UpscaleRequest struct {
+	// Image is the image to upscale.
+	Image Image
+
+	// Factor multiplies the width and height of the image, one of
+	// UpscaleFactors.
+	Factor int
+
+	// MinSide and MaxSide bound the width and height of a local image,
+	// in pixels, with no bound when zero. Images given by URL or in a
+	// format that cannot be decoded are left to the API to check.
+	MinSide int
+	MaxSide int
+}
+
+// UpscaleResult is the upscaled image.
+type UpscaleResult struct {
+	// ImageURL is the upscaled image.
+	ImageURL string `json:"imageUrl"`
+
+	// Width and Height are the size of the upscaled image. They are zero
+	// when neither the API nor the input tells them.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ImageUpscalingService calls the Image Upscaling API.
+type ImageUpscalingService struct {
+	client *Client
+}
+
+// Upscale upscales req.Image by req.Factor, after checking the factor and the
+// size of the image against req.MinSide and req.MaxSide.
+func (s *ImageUpscalingService) Upscale(ctx context.Context, req UpscaleRequest) (*UpscaleResult, error) {
+	if err := req.Image.validate(); err != nil {
+		return nil, err
+	}
+	supported := false
+	for _, factor := range upscaleFactors {
+		supported = supported || req.Factor == factor
+	}
+	if !supported {
+		return nil, fmt.Errorf("%w: upscale factor %d is not one of %v", ErrInvalidRequest, req.Factor, upscaleFactors)
+	}
+	width, height, sized := req.Image.size()
+	if sized {
+		if req.MinSide > 0 && (width < req.MinSide || height < req.MinSide) {
+			return nil, fmt.Errorf("%w: image of %dx%d is smaller than %d pixels a side", ErrInvalidRequest, width, height, req.MinSide)
+		}
+		if req.MaxSide > 0 && (width > req.MaxSide || height > req.MaxSide) {
+			return nil, fmt.Errorf("%w: image of %dx%d is larger than %d pixels a side", ErrInvalidRequest, width, height, req.MaxSide)
+		}
+	}
+
+	request := struct {
+		ImageURL      string `json:"imageUrl,omitempty"`
+		ImageBase64   string `json:"imageBase64,omitempty"`
+		UpscaleFactor int    `json:"upscaleFactor"`
+	}{
+		ImageURL:      req.Image.URL,
+		ImageBase64:   req.Image.base64(),
+		UpscaleFactor: req.Factor,
+	}
+	var result UpscaleResult
+	if err := s.client.Do(ctx, ImageUpscalingAPI, request, &result); err != nil {
+		return nil, err
+	}
+	if result.Width == 0 && sized {
+		result.Width, result.Height = width*req.Factor, height*req.Factor
+	}
+	return &result, nil
+}
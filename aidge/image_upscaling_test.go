@@ -0,0 +1,114 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestUpscale(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/big.png"}))
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	result, err := srv.Client().ImageUpscaling.Upscale(context.Background(), aidge.UpscaleRequest{
+		Image:  aidge.ImageBytes(img.Bytes()),
+		Factor: 3,
+	})
+	if err != nil {
+		t.Fatalf("Upscale: %v", err)
+	}
+	checkBody(t, sentBody(t, srv, aidge.ImageUpscalingAPI), map[string]interface{}{
+		"imageBase64":   base64.StdEncoding.EncodeToString(img.Bytes()),
+		"upscaleFactor": 3.0,
+	})
+	// The size is worked out from the local image when the API does not
+	// tell it.
+	want := aidge.UpscaleResult{ImageURL: "https://example.com/big.png", Width: 120, Height: 90}
+	if *result != want {
+		t.Errorf("Upscale = %+v, want %+v", *result, want)
+	}
+}
+
+func TestUpscaleRejectsUnsupportedFactors(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	_, err := srv.Client().ImageUpscaling.Upscale(context.Background(), aidge.UpscaleRequest{
+		Image:  aidge.ImageURL("https://example.com/a.jpg"),
+		Factor: 8,
+	})
+	if !errors.Is(err, aidge.ErrInvalidRequest) || len(srv.Calls()) != 0 {
+		t.Errorf("Upscale: %v after %d calls, want ErrInvalidRequest before calling", err, len(srv.Calls()))
+	}
+}
+
+func TestUpscaleChecksTheImageSize(t *testing.T) {
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name             string
+		minSide, maxSide int
+		ok               bool
+	}{
+		{"no limits", 0, 0, true},
+		{"within", 30, 40, true},
+		{"too small", 31, 0, false},
+		{"too large", 0, 39, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := aidgetest.NewServer()
+			defer srv.Close()
+			srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/big.png"}))
+
+			_, err := srv.Client().ImageUpscaling.Upscale(context.Background(), aidge.UpscaleRequest{
+				Image:   aidge.ImageBytes(img.Bytes()),
+				Factor:  2,
+				MinSide: tt.minSide,
+				MaxSide: tt.maxSide,
+			})
+			if tt.ok && err != nil {
+				t.Errorf("Upscale: %v", err)
+			}
+			if !tt.ok && (!errors.Is(err, aidge.ErrInvalidRequest) || len(srv.Calls()) != 0) {
+				t.Errorf("Upscale: %v after %d calls, want ErrInvalidRequest before calling", err, len(srv.Calls()))
+			}
+		})
+	}
+}
+
+func TestUpscaleFactorsIsACopy(t *testing.T) {
+	aidge.UpscaleFactors()[0] = 8
+	if got := aidge.UpscaleFactors(); got[0] != 2 {
+		t.Errorf("UpscaleFactors = %v after changing a returned slice", got)
+	}
+}
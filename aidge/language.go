@@ -0,0 +1,47 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+// Language is a language code accepted by the translation APIs.
+type Language string
+
+// Common languages. Other codes supported by an API may be used as
+// Language("xx").
+const (
+	Arabic             Language = "ar"
+	ChineseSimplified  Language = "zh"
+	ChineseTraditional Language = "zh-tw"
+	Dutch              Language = "nl"
+	English            Language = "en"
+	French             Language = "fr"
+	German             Language = "de"
+	Hebrew             Language = "he"
+	Hindi              Language = "hi"
+	Indonesian         Language = "id"
+	Italian            Language = "it"
+	Japanese           Language = "ja"
+	Korean             Language = "ko"
+	Malay              Language = "ms"
+	Polish             Language = "pl"
+	Portuguese         Language = "pt"
+	Russian            Language = "ru"
+	Spanish            Language = "es"
+	Thai               Language = "th"
+	Turkish            Language = "tr"
+	Ukrainian          Language = "uk"
+	Vietnamese         Language = "vi"
+)
@@ -0,0 +1,214 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrWaitExceeded is returned, possibly wrapped, when a PollStrategy gives up
+// before the wait is over.
+var ErrWaitExceeded = errors.New("aidge: maximum wait exceeded")
+
+// PollState describes the progress of a wait to a PollStrategy.
+type PollState struct {
+	// Attempt is the number of queries made so far, starting at 1.
+	Attempt int
+
+	// Elapsed is the time since the wait started.
+	Elapsed time.Duration
+
+	// Hint is the pause suggested by the server with a Retry-After header,
+	// zero when there is none.
+	Hint time.Duration
+}
+
+// PollStrategy decides how long to pause between the queries of a wait.
+type PollStrategy interface {
+	// Next returns the pause before the next query, or false to give up.
+	Next(state PollState) (time.Duration, bool)
+}
+
+// DefaultPoll is used when a TaskSpec has no poll strategy.
+var DefaultPoll PollStrategy = CappedPoll(ExponentialPoll{
+	Initial:    time.Second,
+	Max:        10 * time.Second,
+	Multiplier: 1.5,
+	Jitter:     0.2,
+}, 10*time.Minute)
+
+// WithPoll paces the waits of every task of the client with strategy, in
+// place of the Poll strategy of their TaskSpec.
+func WithPoll(strategy PollStrategy) Option {
+	return func(c *Client) {
+		c.polls.all = strategy
+	}
+}
+
+// WithAPIPoll paces the waits of the tasks submitted to submitAPI, e.g.
+// HandFootRepairTask.SubmitAPI, with strategy, overriding WithPoll.
+func WithAPIPoll(submitAPI string, strategy PollStrategy) Option {
+	return func(c *Client) {
+		c.polls.apis[submitAPI] = strategy
+	}
+}
+
+// polls holds the poll strategies set on a client.
+type polls struct {
+	all  PollStrategy
+	apis map[string]PollStrategy
+}
+
+// apply returns spec with the poll strategy set on the client for it.
+func (p *polls) apply(spec TaskSpec) TaskSpec {
+	if strategy := p.apis[spec.SubmitAPI]; strategy != nil {
+		spec.Poll = strategy
+	} else if p.all != nil {
+		spec.Poll = p.all
+	}
+	return spec
+}
+
+// FixedPoll pauses d between queries, or longer when the server asks to.
+func FixedPoll(d time.Duration) PollStrategy {
+	return fixedPoll(d)
+}
+
+type fixedPoll time.Duration
+
+func (p fixedPoll) Next(state PollState) (time.Duration, bool) {
+	return max(time.Duration(p), state.Hint), true
+}
+
+// ExponentialPoll grows the pause between queries geometrically, so short
+// tasks are picked up quickly while long ones are not queried needlessly.
+type ExponentialPoll struct {
+	// Initial is the first pause, 1s when zero.
+	Initial time.Duration
+
+	// Max caps the pause. Zero means no cap.
+	Max time.Duration
+
+	// Multiplier grows the pause after each query, 2 when zero.
+	Multiplier float64
+
+	// Jitter randomizes each pause by up to this fraction, e.g. 0.2 for
+	// ±20%, to spread out the queries of tasks submitted together.
+	Jitter float64
+}
+
+// Next implements PollStrategy.
+func (p ExponentialPoll) Next(state PollState) (time.Duration, bool) {
+	initial, multiplier := p.Initial, p.Multiplier
+	if initial <= 0 {
+		initial = time.Second
+	}
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	d := float64(initial) * math.Pow(multiplier, float64(max(state.Attempt-1, 0)))
+	if p.Max > 0 {
+		d = math.Min(d, float64(p.Max))
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return max(time.Duration(d), state.Hint), true
+}
+
+// CappedPoll pauses like strategy but gives up once maxWait has elapsed.
+func CappedPoll(strategy PollStrategy, maxWait time.Duration) PollStrategy {
+	return cappedPoll{strategy: strategy, maxWait: maxWait}
+}
+
+type cappedPoll struct {
+	strategy PollStrategy
+	maxWait  time.Duration
+}
+
+func (p cappedPoll) Next(state PollState) (time.Duration, bool) {
+	left := p.maxWait - state.Elapsed
+	if left <= 0 {
+		return 0, false
+	}
+	d, ok := p.strategy.Next(state)
+	return min(d, left), ok
+}
+
+// Poll calls check until it reports done or fails, pausing between calls as
+// decided by strategy, DefaultPoll when nil. check may suggest a pause with
+// hint, zero for none. Poll returns an error wrapping ErrWaitExceeded when the
+// strategy gives up, and returns early with the context's error once ctx is
+// done, so a deadline on ctx bounds the whole wait, including the call in
+// flight.
+func Poll(ctx context.Context, strategy PollStrategy, check func(ctx context.Context) (done bool, hint time.Duration, err error)) error {
+	if strategy == nil {
+		strategy = DefaultPoll
+	}
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		done, hint, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+		pause, ok := strategy.Next(PollState{
+			Attempt: attempt,
+			Elapsed: time.Since(start),
+			Hint:    hint,
+		})
+		if !ok {
+			return ErrWaitExceeded
+		}
+		if err := sleep(ctx, pause); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep pauses for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter returns the pause requested by the Retry-After header, zero when
+// there is none.
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestWithPoll(t *testing.T) {
+	fast := aidge.FixedPoll(time.Millisecond)
+	slow := aidge.FixedPoll(time.Hour)
+	client := aidge.NewClient(aidge.ApiConfig{},
+		aidge.WithPoll(slow),
+		aidge.WithAPIPoll(aidge.HandFootRepairTask.SubmitAPI, fast))
+
+	if got := aidge.NewTask[struct{}](client, aidge.HandFootRepairTask).Spec().Poll; got != fast {
+		t.Errorf("poll of %s = %v, want the WithAPIPoll strategy", aidge.HandFootRepairTask.SubmitAPI, got)
+	}
+	if got := aidge.AttachTask[struct{}](client, aidge.VirtualTryOnProTask, "1").Spec().Poll; got != slow {
+		t.Errorf("poll of %s = %v, want the WithPoll strategy", aidge.VirtualTryOnProTask.SubmitAPI, got)
+	}
+	if got := aidge.NewTask[struct{}](aidge.NewClient(aidge.ApiConfig{}), aidge.VirtualModelTask).Spec().Poll; got != aidge.VirtualModelTask.Poll {
+		t.Errorf("poll without options = %v, want the strategy of the spec", got)
+	}
+}
+
+func TestWithPollPacesTypedServices(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Task(aidge.HandFootRepairTask, aidgetest.Running(), aidgetest.Running(), aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{{"imageUrls": []string{"https://example.com/repaired.png"}}},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	// The default strategy of the spec would not query again within the
+	// deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	results, err := client.HandFootRepair.Repair(ctx, []aidge.RepairItem{{Area: aidge.AreaHand, ImageURL: "https://example.com/hand.png"}})
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if len(results) != 1 || len(results[0].ImageURLs) != 1 {
+		t.Errorf("Repair = %+v, want one repaired image", results)
+	}
+	if n := len(srv.CallsTo(aidge.HandFootRepairTask.ResultAPI)); n != 3 {
+		t.Errorf("%d result queries, want 3", n)
+	}
+}
+
+func TestExponentialPoll(t *testing.T) {
+	tests := []struct {
+		name  string
+		poll  aidge.ExponentialPoll
+		state aidge.PollState
+		want  time.Duration
+	}{
+		{"defaults first", aidge.ExponentialPoll{}, aidge.PollState{Attempt: 1}, time.Second},
+		{"defaults third", aidge.ExponentialPoll{}, aidge.PollState{Attempt: 3}, 4 * time.Second},
+		{"grows", aidge.ExponentialPoll{Initial: 100 * time.Millisecond, Multiplier: 3}, aidge.PollState{Attempt: 3}, 900 * time.Millisecond},
+		{"capped", aidge.ExponentialPoll{Initial: time.Second, Max: 5 * time.Second}, aidge.PollState{Attempt: 10}, 5 * time.Second},
+		{"hint raises", aidge.ExponentialPoll{Initial: time.Second}, aidge.PollState{Attempt: 1, Hint: 7 * time.Second}, 7 * time.Second},
+		{"hint below", aidge.ExponentialPoll{Initial: time.Second}, aidge.PollState{Attempt: 2, Hint: time.Second}, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.poll.Next(tt.state)
+			if got != tt.want || !ok {
+				t.Errorf("Next(%+v) = %v, %v, want %v, true", tt.state, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponentialPollJitter(t *testing.T) {
+	poll := aidge.ExponentialPoll{Initial: time.Second, Max: 4 * time.Second, Jitter: 0.2}
+	for attempt := 1; attempt <= 1000; attempt++ {
+		base := min(time.Second<<min(attempt-1, 10), 4*time.Second)
+		got, _ := poll.Next(aidge.PollState{Attempt: attempt})
+		if low, high := base*8/10, base*12/10; got < low || got > high {
+			t.Fatalf("Next(attempt %d) = %v, want within [%v, %v]", attempt, got, low, high)
+		}
+	}
+}
+
+func TestFixedPoll(t *testing.T) {
+	poll := aidge.FixedPoll(time.Second)
+	if got, ok := poll.Next(aidge.PollState{Attempt: 5}); got != time.Second || !ok {
+		t.Errorf("Next = %v, %v, want 1s, true", got, ok)
+	}
+	if got, _ := poll.Next(aidge.PollState{Attempt: 1, Hint: 3 * time.Second}); got != 3*time.Second {
+		t.Errorf("Next with a 3s hint = %v, want 3s", got)
+	}
+}
+
+func TestCappedPoll(t *testing.T) {
+	poll := aidge.CappedPoll(aidge.FixedPoll(time.Second), 10*time.Second)
+	tests := []struct {
+		elapsed time.Duration
+		want    time.Duration
+		ok      bool
+	}{
+		{0, time.Second, true},
+		{9500 * time.Millisecond, 500 * time.Millisecond, true},
+		{10 * time.Second, 0, false},
+		{time.Minute, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := poll.Next(aidge.PollState{Attempt: 1, Elapsed: tt.elapsed})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Next after %v = %v, %v, want %v, %v", tt.elapsed, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// hintPoll gives up at once, keeping the hint it was given.
+type hintPoll struct {
+	hint *time.Duration
+}
+
+func (p hintPoll) Next(state aidge.PollState) (time.Duration, bool) {
+	*p.hint = state.Hint
+	return 0, false
+}
+
+func TestWaitPassesRetryAfterAndGivesUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		low, high  time.Duration
+	}{
+		{"none", "", 0, 0},
+		{"seconds", "3", 3 * time.Second, 3 * time.Second},
+		{"date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 58 * time.Minute, time.Hour},
+		{"past date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+		{"garbage", "soon", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := aidgetest.NewServer()
+			defer srv.Close()
+			spec := aidge.HandFootRepairTask
+			srv.HandleFunc(spec.ResultAPI, func(aidgetest.Call) aidgetest.Reply {
+				reply := aidgetest.OK(map[string]string{"taskStatus": string(aidge.TaskRunning)})
+				if tt.retryAfter != "" {
+					reply.Header = http.Header{"Retry-After": {tt.retryAfter}}
+				}
+				return reply
+			})
+			var hint time.Duration
+			client := srv.Client(aidge.WithPoll(hintPoll{&hint}))
+
+			_, err := aidge.AttachTask[struct{}](client, spec, "1").Wait(context.Background())
+			if !errors.Is(err, aidge.ErrWaitExceeded) {
+				t.Errorf("Wait: %v, want ErrWaitExceeded", err)
+			}
+			if hint < tt.low || hint > tt.high {
+				t.Errorf("hint = %v, want within [%v, %v]", hint, tt.low, tt.high)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the calls of a Client, to stay under the QPS allowed for
+// an API key. A throttling response from the gateway halves the rate, which
+// then recovers step by step with the calls that succeed. Only the rate
+// adapts: a limit with a MaxInFlight but no QPS does not slow down when
+// throttled, which is left to the retry policy.
+type RateLimit struct {
+	// QPS is the sustained number of calls per second. Zero means no rate
+	// limit.
+	QPS float64
+
+	// Burst is the number of calls that may be made at once, 1 when zero.
+	Burst int
+
+	// MaxInFlight bounds the number of concurrent calls. Zero means no
+	// bound.
+	MaxInFlight int
+}
+
+// WithRateLimit limits all the calls of the client together.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limits.all = newLimiter(limit)
+	}
+}
+
+// WithAPIRateLimit limits the calls to apiName, e.g. "/ai/image/cut/out", on
+// top of the limit set by WithRateLimit.
+func WithAPIRateLimit(apiName string, limit RateLimit) Option {
+	return func(c *Client) {
+		c.limits.apis[apiName] = newLimiter(limit)
+	}
+}
+
+// limits holds the limiters of a client. A nil limiter does not limit.
+type limits struct {
+	all  *limiter
+	apis map[string]*limiter
+}
+
+// acquire waits until a call to apiName may be made and returns the function
+// to call once it is done.
+func (l *limits) acquire(ctx context.Context, apiName string) (release func(throttled bool), err error) {
+	api := l.apis[apiName]
+	if err := api.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.all.acquire(ctx); err != nil {
+		api.release(false)
+		return nil, err
+	}
+	return func(throttled bool) {
+		l.all.release(throttled)
+		api.release(throttled)
+	}, nil
+}
+
+// limiter is a token bucket combined with a bound on concurrent calls.
+type limiter struct {
+	inFlight chan struct{}
+
+	mu      sync.Mutex
+	qps     float64
+	maxQPS  float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	l := &limiter{
+		qps:    limit.QPS,
+		maxQPS: limit.QPS,
+		burst:  math.Max(float64(limit.Burst), 1),
+	}
+	l.tokens = l.burst
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire waits for a free slot and a token.
+func (l *limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := sleep(ctx, l.reserve()); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		l.freeSlot()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token and returns how long to wait until it is valid.
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxQPS <= 0 {
+		return 0
+	}
+	now := time.Now()
+	if !l.updated.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.updated).Seconds()*l.qps)
+	}
+	l.updated = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.qps * float64(time.Second))
+}
+
+// release frees the slot of a call and adapts the rate to its outcome.
+func (l *limiter) release(throttled bool) {
+	if l == nil {
+		return
+	}
+	l.freeSlot()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxQPS <= 0 {
+		return
+	}
+	if throttled {
+		l.qps = math.Max(l.qps/2, l.maxQPS/10)
+	} else {
+		l.qps = math.Min(l.qps+l.maxQPS/20, l.maxQPS)
+	}
+}
+
+func (l *limiter) freeSlot() {
+	if l.inFlight != nil {
+		<-l.inFlight
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiterPacesCalls(t *testing.T) {
+	l := newLimiter(RateLimit{QPS: 20})
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.release(false)
+	}
+	// The first call takes the burst token, the next four wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("5 calls at 20 QPS took %v, want at least 200ms", elapsed)
+	}
+}
+
+func TestLimiterBoundsCallsInFlight(t *testing.T) {
+	l := newLimiter(RateLimit{MaxInFlight: 1})
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second call while the first is in flight: %v, want it blocked until the deadline", err)
+	}
+	l.release(false)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Errorf("call once the first is done: %v", err)
+	}
+}
+
+func TestLimiterStopsWaitingWhenCanceled(t *testing.T) {
+	l := newLimiter(RateLimit{QPS: 0.1, MaxInFlight: 2})
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l.release(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if err := l.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("acquire: %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("acquire returned after %v, want it to stop once canceled", elapsed)
+	}
+	// The canceled call gave back its token and its slot.
+	if len(l.inFlight) != 0 || l.tokens < -0.01 {
+		t.Errorf("after cancel: %d calls in flight, %.2f tokens", len(l.inFlight), l.tokens)
+	}
+}
+
+func TestLimiterSlowsDownWhenThrottled(t *testing.T) {
+	l := newLimiter(RateLimit{QPS: 10})
+	l.release(true)
+	if l.qps != 5 {
+		t.Errorf("QPS after throttling = %v, want 5", l.qps)
+	}
+	for i := 0; i < 10; i++ {
+		l.release(true)
+	}
+	if l.qps != 1 {
+		t.Errorf("QPS after repeated throttling = %v, want the floor of 1", l.qps)
+	}
+	for i := 0; i < 30; i++ {
+		l.release(false)
+	}
+	if l.qps != 10 {
+		t.Errorf("QPS after successes = %v, want it back to 10", l.qps)
+	}
+}
+
+func TestLimitsApplyPerAPI(t *testing.T) {
+	client := NewClient(ApiConfig{}, WithAPIRateLimit("/limited", RateLimit{MaxInFlight: 1}))
+	release, err := client.limits.acquire(context.Background(), "/limited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.limits.acquire(ctx, "/limited"); err == nil {
+		t.Error("second call to the limited API was not held back")
+	}
+	other, err := client.limits.acquire(ctx, "/other")
+	if err != nil {
+		t.Errorf("call to another API: %v, want it unlimited", err)
+	} else {
+		other(false)
+	}
+	release(false)
+}
+
+func TestThrottledResponsesSlowTheLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"Throttling","message":"too many calls"}`))
+	}))
+	defer srv.Close()
+	client := NewClient(ApiConfig{ApiDomain: srv.URL},
+		WithRetry(RetryPolicy{}),
+		WithAPIRateLimit("/ai/test", RateLimit{QPS: 8}))
+
+	var apiErr *APIError
+	if err := client.Do(context.Background(), "/ai/test", nil, nil); !errors.As(err, &apiErr) || apiErr.Kind != KindThrottled {
+		t.Fatalf("Do: %v, want a throttled APIError", err)
+	}
+	if qps := client.limits.apis["/ai/test"].qps; qps != 4 {
+		t.Errorf("QPS after a throttled response = %v, want 4", qps)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Response is the envelope of every gateway response. The API specific
+// result is in Data.
+type Response struct {
+	// Code is "0" on success and an error code such as "MissingParameter"
+	// otherwise.
+	Code string `json:"code"`
+
+	// Type is the side blamed for a failure, e.g. "ISV" or "ISP".
+	Type string `json:"type,omitempty"`
+
+	// Message describes a failure.
+	Message string `json:"message,omitempty"`
+
+	// RequestID identifies the call for Aidge support.
+	RequestID string `json:"request_id"`
+
+	// Data is the result of the API.
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// Header holds the HTTP headers of the response.
+	Header http.Header `json:"-"`
+}
+
+// UnmarshalJSON decodes the envelope, accepting a numeric Code.
+func (r *Response) UnmarshalJSON(b []byte) error {
+	type response Response
+	v := struct {
+		*response
+		Code flexString `json:"code"`
+	}{response: (*response)(r)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	r.Code = string(v.Code)
+	return nil
+}
+
+// Success reports whether the response carries no error code.
+func (r *Response) Success() bool {
+	return r.Code == "" || r.Code == "0"
+}
+
+// err returns the failure reported by the response to apiName, or nil. Some
+// APIs succeed at the gateway but report a failure in the data with
+// "success": false.
+func (r *Response) err(apiName string, statusCode int) *APIError {
+	code, message := r.Code, r.Message
+	if r.Success() {
+		var data struct {
+			Success *bool      `json:"success"`
+			Code    flexString `json:"code"`
+			Message string     `json:"message"`
+		}
+		if json.Unmarshal(r.Data, &data) == nil && data.Success != nil && !*data.Success {
+			code, message = string(data.Code), data.Message
+		} else if statusCode >= 200 && statusCode <= 299 {
+			return nil
+		} else {
+			code = strconv.Itoa(statusCode)
+		}
+	}
+	return &APIError{
+		APIName:    apiName,
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+		Type:       r.Type,
+		RequestID:  r.RequestID,
+		Kind:       classify(statusCode, code, message, r.Type),
+	}
+}
+
+// httpError returns the failure of a response to apiName whose body is not an
+// envelope.
+func httpError(apiName string, statusCode int, body []byte) *APIError {
+	const maxMessage = 512
+	if len(body) > maxMessage {
+		body = body[:maxMessage]
+	}
+	code := strconv.Itoa(statusCode)
+	message := http.StatusText(statusCode)
+	if len(body) > 0 {
+		message = string(body)
+	}
+	return &APIError{
+		APIName:    apiName,
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+		Kind:       classify(statusCode, code, message, ""),
+	}
+}
+
+// flexString decodes a JSON string or number as a string.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = flexString(v)
+		return nil
+	}
+	*s = flexString(b)
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryPolicy decides how often a failed call is attempted again. Network
+// errors, server errors and throttling are retried, each attempt signed with
+// a fresh timestamp. Calls that create a task are only resent when marked
+// Idempotent, except after throttling, which the gateway reports before the
+// task is created.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of a call, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// Backoff paces the attempts. The Hint of its PollState is the
+	// Retry-After of the failed response. DefaultRetryPolicy's backoff is
+	// used when nil.
+	Backoff PollStrategy
+}
+
+// DefaultRetryPolicy is the retry policy of a Client without WithRetry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff: ExponentialPoll{
+		Initial:    500 * time.Millisecond,
+		Max:        5 * time.Second,
+		Multiplier: 2,
+		Jitter:     0.2,
+	},
+}
+
+// WithRetry sets the retry policy of the client. WithRetry(RetryPolicy{})
+// disables retries.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// CallOption configures a single call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	idempotent bool
+}
+
+// Idempotent marks a call as safe to resend. Use it for task creation calls
+// whose request carries a dedupe key, such as the requestBizId of the hands
+// and feet repair API, so that a lost response does not pay for a second
+// task.
+func Idempotent() CallOption {
+	return func(o *callOptions) {
+		o.idempotent = true
+	}
+}
+
+// taskCreatingAPIs are not resent unless marked Idempotent.
+var taskCreatingAPIs = func() map[string]bool {
+	apis := map[string]bool{}
+	for _, s := range catalog {
+		if s.Async {
+			apis[s.Path] = true
+		}
+	}
+	return apis
+}()
+
+// shouldRetry reports whether the failed attempt of a call to apiName may be
+// made again. transient tells whether err is a network or server failure.
+func (p *RetryPolicy) shouldRetry(attempt int, apiName string, opts *callOptions, err error, transient bool) bool {
+	if attempt >= p.MaxAttempts || !transient {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Kind == KindThrottled {
+		return true
+	}
+	return opts.idempotent || !taskCreatingAPIs[apiName]
+}
+
+func (p *RetryPolicy) backoff() PollStrategy {
+	if p.Backoff != nil {
+		return p.Backoff
+	}
+	return DefaultRetryPolicy.Backoff
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	network := errors.New("connection reset")
+	throttled := &APIError{Code: "Throttling", Kind: KindThrottled}
+	server := &APIError{Code: "InternalError", Kind: KindServer}
+	invalid := &APIError{Code: "InvalidParameter", Kind: KindInvalidParameter}
+	submit := HandFootRepairTask.SubmitAPI
+
+	for _, tt := range []struct {
+		name       string
+		attempt    int
+		apiName    string
+		idempotent bool
+		err        error
+		transient  bool
+		want       bool
+	}{
+		{"network failure", 1, BackgroundRemovalAPI, false, network, true, true},
+		{"server failure", 1, BackgroundRemovalAPI, false, server, true, true},
+		{"permanent failure", 1, BackgroundRemovalAPI, false, invalid, false, false},
+		{"last attempt", 3, BackgroundRemovalAPI, false, network, true, false},
+		{"task submit", 1, submit, false, network, true, false},
+		{"task submit on server failure", 1, submit, false, server, true, false},
+		{"idempotent task submit", 1, submit, true, network, true, true},
+		{"throttled task submit", 1, submit, false, throttled, true, true},
+		{"task result query", 1, HandFootRepairTask.ResultAPI, false, network, true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &callOptions{idempotent: tt.idempotent}
+			if got := policy.shouldRetry(tt.attempt, tt.apiName, opts, tt.err, tt.transient); got != tt.want {
+				t.Errorf("shouldRetry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskCreatingAPIs(t *testing.T) {
+	for _, spec := range []TaskSpec{VirtualTryOnProTask, HandFootRepairTask, ImageTranslationProTask, VirtualModelTask} {
+		if !taskCreatingAPIs[spec.SubmitAPI] {
+			t.Errorf("%s is not a task creating API", spec.SubmitAPI)
+		}
+		if taskCreatingAPIs[spec.ResultAPI] {
+			t.Errorf("%s is a task creating API", spec.ResultAPI)
+		}
+	}
+}
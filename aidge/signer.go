@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned, possibly wrapped, by Signer.Verify when a
+// request is not correctly signed.
+var ErrInvalidSignature = errors.New("aidge: invalid signature")
+
+// Signer signs gateway requests with the sha256 (sign_ver=v2) scheme. The sign
+// is the upper-case hex HMAC-SHA256, keyed by the secret, of the secret
+// followed by the timestamp in milliseconds. It is placed in the query string
+// together with partner_id=aidge&sign_method=sha256&sign_ver=v2, the app_key
+// and the timestamp.
+type Signer struct {
+	// AccessKeyName is sent as the app_key.
+	AccessKeyName string
+
+	// AccessKeySecret keys the HMAC.
+	AccessKeySecret string
+
+	// Now returns the signing time. It defaults to time.Now.
+	Now func() time.Time
+
+	// MaxSkew is the largest difference between a verified timestamp and
+	// Now. Zero disables the check.
+	MaxSkew time.Duration
+}
+
+// NewSigner returns a Signer for the given API key.
+func NewSigner(accessKeyName, accessKeySecret string) *Signer {
+	return &Signer{
+		AccessKeyName:   accessKeyName,
+		AccessKeySecret: accessKeySecret,
+	}
+}
+
+// Sign returns a copy of params with the signing parameters added.
+func (s *Signer) Sign(params url.Values) url.Values {
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	timestamp := strconv.FormatInt(s.now().UnixMilli(), 10)
+	query.Set("partner_id", "aidge")
+	query.Set("sign_method", "sha256")
+	query.Set("sign_ver", "v2")
+	query.Set("app_key", s.AccessKeyName)
+	query.Set("timestamp", timestamp)
+	query.Set("sign", s.sign(timestamp))
+	return query
+}
+
+// SignURL returns the signed URL of apiName, e.g. "/ai/text/marco/translator",
+// on apiDomain with the extra query params. apiDomain is either a host such as
+// DefaultApiDomain, reached over https, or a base URL with a scheme.
+func (s *Signer) SignURL(apiDomain, apiName string, params url.Values) string {
+	return baseURL(apiDomain) + "/rest" + apiName + "?" + s.Sign(params).Encode()
+}
+
+// Verify checks the signing parameters of query, as found in the URL of a
+// signed request. It returns an error wrapping ErrInvalidSignature if they are
+// missing, were made for another key, do not match the secret, or carry a
+// timestamp further than MaxSkew from Now.
+func (s *Signer) Verify(query url.Values) error {
+	for _, p := range [...]struct{ param, want string }{
+		{"partner_id", "aidge"},
+		{"sign_method", "sha256"},
+		{"sign_ver", "v2"},
+		{"app_key", s.AccessKeyName},
+	} {
+		if got := query.Get(p.param); got != p.want {
+			return fmt.Errorf("%w: %s is %q, want %q", ErrInvalidSignature, p.param, got, p.want)
+		}
+	}
+
+	timestamp := query.Get("timestamp")
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp %q", ErrInvalidSignature, timestamp)
+	}
+	if s.MaxSkew > 0 {
+		skew := s.now().Sub(time.UnixMilli(millis))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > s.MaxSkew {
+			return fmt.Errorf("%w: timestamp is %v off", ErrInvalidSignature, skew)
+		}
+	}
+
+	sign := strings.ToUpper(query.Get("sign"))
+	if !hmac.Equal([]byte(sign), []byte(s.sign(timestamp))) {
+		return fmt.Errorf("%w: sign does not match", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// sign calculates the SHA256 HMAC for timestamp.
+func (s *Signer) sign(timestamp string) string {
+	h := hmac.New(sha256.New, []byte(s.AccessKeySecret))
+	h.Write([]byte(s.AccessKeySecret + timestamp))
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+}
+
+func (s *Signer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// baseURL returns the URL the REST paths of apiDomain hang off.
+func baseURL(apiDomain string) string {
+	if !strings.Contains(apiDomain, "://") {
+		apiDomain = "https://" + apiDomain
+	}
+	return strings.TrimRight(apiDomain, "/")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// signedAt is the fixed clock of the tests, 1733702400000 in milliseconds.
+var signedAt = time.Date(2024, 12, 9, 0, 0, 0, 0, time.UTC)
+
+func fixedSigner() *aidge.Signer {
+	signer := aidge.NewSigner("512345", "test-secret")
+	signer.Now = func() time.Time { return signedAt }
+	return signer
+}
+
+func TestSignerSign(t *testing.T) {
+	query := fixedSigner().Sign(url.Values{"taskId": {"42"}})
+
+	want := map[string]string{
+		"partner_id":  "aidge",
+		"sign_method": "sha256",
+		"sign_ver":    "v2",
+		"app_key":     "512345",
+		"timestamp":   "1733702400000",
+		"sign":        "B6E16E6C8B7D27407131B9C2B4BFE4B631BD7C285FF702C24D664ACBF3ACD09E",
+		"taskId":      "42",
+	}
+	for param, value := range want {
+		if got := query.Get(param); got != value {
+			t.Errorf("%s = %q, want %q", param, got, value)
+		}
+	}
+
+	// The sign computed as the examples did before the client existed.
+	h := hmac.New(sha256.New, []byte("test-secret"))
+	h.Write([]byte("test-secret" + "1733702400000"))
+	if inline := strings.ToUpper(hex.EncodeToString(h.Sum(nil))); query.Get("sign") != inline {
+		t.Errorf("sign = %s, want %s as signed inline", query.Get("sign"), inline)
+	}
+}
+
+func TestSignerSignURL(t *testing.T) {
+	for _, tt := range []struct {
+		domain, prefix string
+	}{
+		{aidge.DefaultApiDomain, "https://api.aidc-ai.com/rest/ai/image/cut/out?"},
+		{"http://127.0.0.1:8080", "http://127.0.0.1:8080/rest/ai/image/cut/out?"},
+	} {
+		got := fixedSigner().SignURL(tt.domain, aidge.BackgroundRemovalAPI, nil)
+		if !strings.HasPrefix(got, tt.prefix) {
+			t.Errorf("SignURL(%q) = %s, want prefix %s", tt.domain, got, tt.prefix)
+		}
+	}
+}
+
+func TestSignerVerify(t *testing.T) {
+	signed := fixedSigner().Sign(nil)
+	with := func(param, value string) url.Values {
+		query := url.Values{}
+		for k, v := range signed {
+			query[k] = v
+		}
+		if value == "" {
+			query.Del(param)
+		} else {
+			query.Set(param, value)
+		}
+		return query
+	}
+
+	for _, tt := range []struct {
+		name  string
+		query url.Values
+		skew  time.Duration
+		ok    bool
+	}{
+		{"valid", signed, 0, true},
+		{"lower case sign", with("sign", strings.ToLower(signed.Get("sign"))), 0, true},
+		{"other key", with("app_key", "99999"), 0, false},
+		{"tampered sign", with("sign", "00"+signed.Get("sign")[2:]), 0, false},
+		{"tampered timestamp", with("timestamp", "1733702400001"), 0, false},
+		{"no timestamp", with("timestamp", ""), 0, false},
+		{"md5", with("sign_method", "md5"), 0, false},
+		{"within skew", signed, time.Minute, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := fixedSigner()
+			verifier.MaxSkew = tt.skew
+			err := verifier.Verify(tt.query)
+			if tt.ok && err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, aidge.ErrInvalidSignature) {
+				t.Errorf("Verify = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	late := fixedSigner()
+	late.MaxSkew = time.Minute
+	late.Now = func() time.Time { return signedAt.Add(2 * time.Minute) }
+	if err := late.Verify(signed); !errors.Is(err, aidge.ErrInvalidSignature) {
+		t.Errorf("Verify of a stale timestamp = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestGatewayVerifiesSignature(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.BackgroundRemovalAPI, aidgetest.OK(map[string]string{"imageUrl": "https://example.com/out.png"}))
+	ctx := context.Background()
+
+	if err := srv.Client().Do(ctx, aidge.BackgroundRemovalAPI, "{}", nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	config := srv.Config()
+	config.AccessKeySecret = "wrong"
+	err := aidge.NewClient(config).Do(ctx, aidge.BackgroundRemovalAPI, "{}", nil)
+	var apiErr *aidge.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != aidge.KindAuth || apiErr.Code != "InvalidSignature" {
+		t.Errorf("Do with a wrong secret = %v, want an InvalidSignature auth error", err)
+	}
+}
@@ -0,0 +1,329 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TaskStatus is the state of an asynchronous task, as reported in the
+// taskStatus field of its results.
+type TaskStatus string
+
+// Task states reported by the result APIs. Besides TaskFinished, any of the
+// failure states ends a task.
+const (
+	TaskRunning  TaskStatus = "running"
+	TaskFinished TaskStatus = "finished"
+	TaskFailed   TaskStatus = "failed"
+)
+
+var failedTaskStatuses = map[TaskStatus]bool{
+	TaskFailed:  true,
+	"fail":      true,
+	"error":     true,
+	"canceled":  true,
+	"cancelled": true,
+	"timeout":   true,
+	"expired":   true,
+}
+
+// Failed reports whether s is a terminal failure.
+func (s TaskStatus) Failed() bool {
+	return failedTaskStatuses[TaskStatus(strings.ToLower(string(s)))]
+}
+
+// Done reports whether the task has ended, successfully or not.
+func (s TaskStatus) Done() bool {
+	return strings.EqualFold(string(s), string(TaskFinished)) || s.Failed()
+}
+
+// TaskSpec describes an asynchronous API: the API that submits the task and
+// the API that reports its status and results.
+type TaskSpec struct {
+	// SubmitAPI creates the task and answers with its taskId.
+	SubmitAPI string
+
+	// ResultAPI reports the status and results of a task.
+	ResultAPI string
+
+	// ResultMethod is the HTTP method of ResultAPI, http.MethodPost when
+	// empty. With http.MethodGet the task id is sent in the query string.
+	ResultMethod string
+
+	// IDField is the name under which ResultAPI expects the task id.
+	IDField string
+
+	// Poll paces the status queries of Wait, DefaultPoll when nil. WithPoll
+	// and WithAPIPoll override it for the tasks of a client.
+	Poll PollStrategy
+}
+
+// Asynchronous APIs.
+var (
+	// VirtualTryOnProTask is the Virtual Try-On Pro API.
+	VirtualTryOnProTask = TaskSpec{
+		SubmitAPI: "/ai/virtual/tryon-pro",
+		ResultAPI: "/ai/virtual/tryon-results",
+		IDField:   "task_id",
+
+		// Try-on images usually take about half a minute.
+		Poll: CappedPoll(ExponentialPoll{
+			Initial:    3 * time.Second,
+			Max:        10 * time.Second,
+			Multiplier: 1.5,
+			Jitter:     0.2,
+		}, 10*time.Minute),
+	}
+
+	// HandFootRepairTask is the Hands and Feet Repair API.
+	HandFootRepairTask = TaskSpec{
+		SubmitAPI: "/ai/hand-foot/repair",
+		ResultAPI: "/ai/hand-foot/repair-results",
+		IDField:   "taskId",
+		Poll: CappedPoll(ExponentialPoll{
+			Initial:    5 * time.Second,
+			Max:        15 * time.Second,
+			Multiplier: 1.5,
+			Jitter:     0.2,
+		}, 10*time.Minute),
+	}
+
+	// ImageTranslationProTask is the Image Translation Pro batch API.
+	ImageTranslationProTask = TaskSpec{
+		SubmitAPI:    "/ai/image/translation_mllm/batch",
+		ResultAPI:    "/ai/image/translation_mllm/results",
+		ResultMethod: http.MethodGet,
+		IDField:      "taskId",
+
+		// A batch takes a few seconds per image.
+		Poll: CappedPoll(ExponentialPoll{
+			Initial:    2 * time.Second,
+			Max:        15 * time.Second,
+			Multiplier: 1.5,
+			Jitter:     0.2,
+		}, 30*time.Minute),
+	}
+
+	// VirtualModelTask is the Virtual Model Alternation API.
+	VirtualModelTask = TaskSpec{
+		SubmitAPI: "/ai/virtual/model/generation/batch",
+		ResultAPI: "/ai/virtual/model/generation/query",
+		IDField:   "taskId",
+		Poll: CappedPoll(ExponentialPoll{
+			Initial:    3 * time.Second,
+			Max:        10 * time.Second,
+			Multiplier: 1.5,
+			Jitter:     0.2,
+		}, 10*time.Minute),
+	}
+)
+
+// TaskResult is a snapshot of a task.
+type TaskResult[T any] struct {
+	// TaskID identifies the task.
+	TaskID string
+
+	// Status is the state of the task.
+	Status TaskStatus
+
+	// Data is the data of the result API response, holding the results once
+	// the task is finished.
+	Data T
+
+	// RetryAfter is the pause before the next query suggested by the
+	// server, zero when there is none.
+	RetryAfter time.Duration
+
+	raw json.RawMessage
+}
+
+// TaskFailedError is returned by Task.Wait when a task ends in a failure
+// state.
+type TaskFailedError struct {
+	APIName string
+	TaskID  string
+	Status  TaskStatus
+	Code    string
+	Message string
+}
+
+func (e *TaskFailedError) Error() string {
+	msg := fmt.Sprintf("aidge: %s: task %s %s", e.APIName, e.TaskID, e.Status)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Task is an asynchronous task whose results decode into T.
+type Task[T any] struct {
+	client *Client
+	spec   TaskSpec
+	id     string
+}
+
+// NewTask returns a task of spec to be submitted with Submit.
+func NewTask[T any](client *Client, spec TaskSpec) *Task[T] {
+	return &Task[T]{client: client, spec: client.polls.apply(spec)}
+}
+
+// AttachTask returns the already submitted task of spec with the given id.
+func AttachTask[T any](client *Client, spec TaskSpec, id string) *Task[T] {
+	return &Task[T]{client: client, spec: client.polls.apply(spec), id: id}
+}
+
+// ID returns the id of the task, empty until it is submitted.
+func (t *Task[T]) ID() string {
+	return t.id
+}
+
+// Spec returns the description of the API of the task.
+func (t *Task[T]) Spec() TaskSpec {
+	return t.spec
+}
+
+// Submit creates the task by calling the submit API with request, which is
+// encoded like in Client.Do. A failed submit is only retried when opts
+// include Idempotent. When the client has a task store, the task is recorded
+// in it; failing to do so is reported as a *TaskRecordError even though the
+// task is submitted, and ID returns its id.
+func (t *Task[T]) Submit(ctx context.Context, request interface{}, opts ...CallOption) error {
+	body, err := encodeRequest(request)
+	if err != nil {
+		return fmt.Errorf("aidge: %s: encoding request: %w", t.spec.SubmitAPI, err)
+	}
+	var data struct {
+		TaskID flexString `json:"taskId"`
+		Result struct {
+			TaskID flexString `json:"taskId"`
+		} `json:"result"`
+	}
+	if err := t.client.Do(ctx, t.spec.SubmitAPI, json.RawMessage(body), &data, opts...); err != nil {
+		return err
+	}
+	id := string(data.Result.TaskID)
+	if id == "" {
+		id = string(data.TaskID)
+	}
+	if id == "" {
+		return fmt.Errorf("aidge: %s: no taskId in response", t.spec.SubmitAPI)
+	}
+	t.id = id
+	return t.client.recordSubmit(ctx, t.spec, id, body)
+}
+
+// Status queries the current state of the task.
+func (t *Task[T]) Status(ctx context.Context) (*TaskResult[T], error) {
+	if t.id == "" {
+		return nil, fmt.Errorf("aidge: %s: task not submitted", t.spec.SubmitAPI)
+	}
+
+	var resp Response
+	var err error
+	if t.spec.ResultMethod == http.MethodGet {
+		err = t.client.Get(ctx, t.spec.ResultAPI, url.Values{t.spec.IDField: {t.id}}, &resp)
+	} else {
+		err = t.client.Do(ctx, t.spec.ResultAPI, map[string]string{t.spec.IDField: t.id}, &resp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw := resp.Data
+
+	var state struct {
+		TaskStatus flexString `json:"taskStatus"`
+	}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("aidge: %s: decoding task status: %w", t.spec.ResultAPI, err)
+	}
+	result := &TaskResult[T]{
+		TaskID:     t.id,
+		Status:     TaskStatus(state.TaskStatus),
+		RetryAfter: retryAfter(resp.Header),
+		raw:        raw,
+	}
+	if err := json.Unmarshal(raw, &result.Data); err != nil {
+		return nil, fmt.Errorf("aidge: %s: decoding task result: %w", t.spec.ResultAPI, err)
+	}
+	return result, nil
+}
+
+// Wait polls the task, paced by the Poll strategy of its spec, until it
+// finishes and returns its results. A task ending in a failure state is
+// reported as a *TaskFailedError and giving up as an error wrapping
+// ErrWaitExceeded. Waiting stops with the context's error once ctx is done.
+// When the client has a task store, the outcome of a task that is done is
+// recorded in it.
+func (t *Task[T]) Wait(ctx context.Context) (T, error) {
+	var result *TaskResult[T]
+	err := Poll(ctx, t.spec.Poll, func(ctx context.Context) (bool, time.Duration, error) {
+		var err error
+		result, err = t.Status(ctx)
+		if err != nil {
+			return false, 0, err
+		}
+		return result.Status.Done(), result.RetryAfter, nil
+	})
+	if errors.Is(err, ErrWaitExceeded) {
+		err = fmt.Errorf("%w waiting for task %s of %s", err, t.id, t.spec.SubmitAPI)
+	}
+	if err == nil && result.Status.Failed() {
+		err = t.failure(result)
+	}
+	if result != nil && result.Status.Done() {
+		if recordErr := t.client.recordDone(ctx, t.spec, t.id, result.Status, result.raw, err); recordErr != nil && err == nil {
+			err = recordErr
+		}
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result.Data, nil
+}
+
+// failure returns the error of a task that ended in a failure state.
+func (t *Task[T]) failure(result *TaskResult[T]) error {
+	var detail struct {
+		ErrorCode    flexString `json:"errorCode"`
+		ErrorMessage string     `json:"errorMessage"`
+		Message      string     `json:"message"`
+	}
+	json.Unmarshal(result.raw, &detail)
+	message := detail.ErrorMessage
+	if message == "" {
+		message = detail.Message
+	}
+	return &TaskFailedError{
+		APIName: t.spec.ResultAPI,
+		TaskID:  t.id,
+		Status:  result.Status,
+		Code:    string(detail.ErrorCode),
+		Message: message,
+	}
+}
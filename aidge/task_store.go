@@ -0,0 +1,448 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrTaskNotFound is returned by TaskStore.Load for a task it does not hold.
+var ErrTaskNotFound = errors.New("aidge: task not found")
+
+// TaskRecord is a submitted task as kept by a TaskStore.
+type TaskRecord struct {
+	// ID is the id of the task.
+	ID string `json:"id"`
+
+	// API is the submit API of the task.
+	API string `json:"api"`
+
+	// Input is the JSON body of the submit. FileTaskStore leaves it out of
+	// the records it could not read back otherwise, see maxJournalLine.
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// Status is the last known state of the task.
+	Status TaskStatus `json:"status"`
+
+	// Result is the data of the result API once the task is finished.
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Error is the failure of the task.
+	Error string `json:"error,omitempty"`
+
+	SubmittedAt time.Time `json:"submittedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TaskStore keeps the records of submitted tasks so that they can be resumed
+// by another process. Implementations must be safe for concurrent use.
+type TaskStore interface {
+	// Save creates or replaces the record of a task.
+	Save(ctx context.Context, record TaskRecord) error
+
+	// Load returns the record of task id of api, or ErrTaskNotFound.
+	Load(ctx context.Context, api, id string) (TaskRecord, error)
+
+	// Unfinished returns the records of the tasks that are not done, oldest
+	// first.
+	Unfinished(ctx context.Context) ([]TaskRecord, error)
+}
+
+// WithTaskStore records every task submitted by the client, and its outcome
+// once waited for, in store. See Client.Resume.
+//
+// A task that is submitted but cannot be recorded is still running: Task.Submit
+// and the Submit methods of the typed services report a *TaskRecordError
+// along with the submitted task or batch, so that it can be waited for.
+func WithTaskStore(store TaskStore) Option {
+	return func(c *Client) {
+		c.tasks = store
+	}
+}
+
+// Resume waits for the unfinished tasks of the task store of the client, as
+// left by a process that stopped before they were done, and returns their
+// records once they are. The outcome of each task is saved in the store. A
+// task whose status the result API refuses for good, e.g. because the
+// gateway no longer knows it, is saved as failed with the refusal. Resume
+// returns an error for the tasks it could not wait for, along with their
+// records as they stand.
+func (c *Client) Resume(ctx context.Context) ([]TaskRecord, error) {
+	if c.tasks == nil {
+		return nil, errors.New("aidge: the client has no task store")
+	}
+	records, err := c.tasks.Unfinished(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aidge: loading tasks: %w", err)
+	}
+
+	errs := make([]error, len(records))
+	var wg sync.WaitGroup
+	for i, record := range records {
+		service, ok := LookupService(record.API)
+		spec, async := service.Task()
+		if !ok || !async {
+			errs[i] = fmt.Errorf("aidge: %s: no task API to resume task %s", record.API, record.ID)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, spec TaskSpec, id string) {
+			defer wg.Done()
+			_, err := AttachTask[json.RawMessage](c, spec, id).Wait(ctx)
+			var failed *TaskFailedError
+			var apiErr *APIError
+			switch {
+			case err == nil, errors.As(err, &failed):
+			case errors.As(err, &apiErr) && taskGone(apiErr):
+				// Waiting again would fail the same way.
+				errs[i] = c.recordDone(ctx, spec, id, TaskFailed, nil, apiErr)
+			default:
+				errs[i] = err
+			}
+		}(i, spec, record.ID)
+	}
+	wg.Wait()
+
+	for i, record := range records {
+		if r, err := c.tasks.Load(ctx, record.API, record.ID); err == nil {
+			records[i] = r
+		}
+	}
+	return records, errors.Join(errs...)
+}
+
+// TaskRecordError is a failure to record a task in the task store of the
+// client.
+type TaskRecordError struct {
+	// APIName is the submit API of the task.
+	APIName string
+
+	// TaskID identifies the task.
+	TaskID string
+
+	// Err is the failure of the task store.
+	Err error
+}
+
+func (e *TaskRecordError) Error() string {
+	return fmt.Sprintf("aidge: %s: recording task %s: %v", e.APIName, e.TaskID, e.Err)
+}
+
+func (e *TaskRecordError) Unwrap() error {
+	return e.Err
+}
+
+// submitted returns the value build makes of a task once its submit returned
+// err, along with err when it is a *TaskRecordError, and only err when the
+// task was not submitted.
+func submitted[V any](err error, build func() V) (V, error) {
+	var recordErr *TaskRecordError
+	if err != nil && !errors.As(err, &recordErr) {
+		var zero V
+		return zero, err
+	}
+	return build(), err
+}
+
+// taskGone reports whether err, returned while waiting for a task, is about
+// the task rather than the caller and will not go away.
+func taskGone(err *APIError) bool {
+	return !err.transient() && err.Kind != KindAuth && err.Kind != KindQuotaExhausted
+}
+
+// recordSubmit saves the record of a task just submitted.
+func (c *Client) recordSubmit(ctx context.Context, spec TaskSpec, id string, input []byte) error {
+	if c.tasks == nil {
+		return nil
+	}
+	now := time.Now()
+	err := c.tasks.Save(ctx, TaskRecord{
+		ID:          id,
+		API:         spec.SubmitAPI,
+		Input:       input,
+		Status:      TaskRunning,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return &TaskRecordError{APIName: spec.SubmitAPI, TaskID: id, Err: err}
+	}
+	return nil
+}
+
+// recordDone saves the outcome of a task that is done.
+func (c *Client) recordDone(ctx context.Context, spec TaskSpec, id string, status TaskStatus, result json.RawMessage, taskErr error) error {
+	if c.tasks == nil {
+		return nil
+	}
+	record, err := c.tasks.Load(ctx, spec.SubmitAPI, id)
+	if errors.Is(err, ErrTaskNotFound) {
+		record = TaskRecord{ID: id, API: spec.SubmitAPI}
+	} else if err != nil {
+		return &TaskRecordError{APIName: spec.SubmitAPI, TaskID: id, Err: err}
+	}
+	record.Status = status
+	record.Result = result
+	record.Error = ""
+	if taskErr != nil {
+		record.Error = taskErr.Error()
+	}
+	record.UpdatedAt = time.Now()
+	if err := c.tasks.Save(ctx, record); err != nil {
+		return &TaskRecordError{APIName: spec.SubmitAPI, TaskID: id, Err: err}
+	}
+	return nil
+}
+
+// taskKey identifies a task in a store.
+type taskKey struct {
+	api, id string
+}
+
+// MemoryTaskStore is a TaskStore holding the records in memory, for tests and
+// processes that only resume the tasks they submitted themselves.
+type MemoryTaskStore struct {
+	mu      sync.Mutex
+	records map[taskKey]TaskRecord
+}
+
+// NewMemoryTaskStore returns an empty MemoryTaskStore.
+func NewMemoryTaskStore() *MemoryTaskStore {
+	return &MemoryTaskStore{records: map[taskKey]TaskRecord{}}
+}
+
+// Save creates or replaces the record of a task.
+func (s *MemoryTaskStore) Save(ctx context.Context, record TaskRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[taskKey{record.API, record.ID}] = record
+	return nil
+}
+
+// Load returns the record of task id of api, or ErrTaskNotFound.
+func (s *MemoryTaskStore) Load(ctx context.Context, api, id string) (TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[taskKey{api, id}]
+	if !ok {
+		return TaskRecord{}, ErrTaskNotFound
+	}
+	return record, nil
+}
+
+// Unfinished returns the records of the tasks that are not done, oldest
+// first.
+func (s *MemoryTaskStore) Unfinished(ctx context.Context) ([]TaskRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []TaskRecord
+	for _, record := range s.records {
+		if !record.Status.Done() {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].SubmittedAt.Equal(records[j].SubmittedAt) {
+			return records[i].SubmittedAt.Before(records[j].SubmittedAt)
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+// FileTaskStore is a TaskStore journaling the records to a local file, one
+// JSON line per change, synced before Save returns. The journal is compacted
+// when it is opened, dropping the records of the tasks that are done.
+type FileTaskStore struct {
+	// memory holds the records, kept unexported so that they are only
+	// changed through the journal.
+	memory MemoryTaskStore
+
+	path string
+	file *os.File
+}
+
+// OpenFileTaskStore opens the journal at path, creating it if needed.
+func OpenFileTaskStore(path string) (*FileTaskStore, error) {
+	s := &FileTaskStore{
+		memory: MemoryTaskStore{records: map[taskKey]TaskRecord{}},
+		path:   path,
+	}
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("aidge: opening task store: %w", err)
+	}
+	if err := s.compact(); err != nil {
+		return nil, fmt.Errorf("aidge: opening task store: %w", err)
+	}
+	return s, nil
+}
+
+// maxJournalLine is the length of the longest record load reads. Save keeps
+// the records within it, so that only lines written by others are skipped.
+const maxJournalLine = 16 << 20
+
+// load reads the journal. A truncated last line, left by a crash while
+// saving, is ignored, and so are the lines longer than maxJournalLine.
+func (s *FileTaskStore) load() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := readLine(r, maxJournalLine)
+		var record TaskRecord
+		if json.Unmarshal(line, &record) == nil && record.ID != "" {
+			s.memory.records[taskKey{record.API, record.ID}] = record
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readLine returns the next line of r, nil when it is longer than max.
+func readLine(r *bufio.Reader, max int) ([]byte, error) {
+	var line []byte
+	long := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !long {
+			line = append(line, chunk...)
+			if len(line) > max {
+				line, long = nil, true
+			}
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// compact rewrites the journal with the records of the unfinished tasks and
+// opens it for appending.
+func (s *FileTaskStore) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for key, record := range s.memory.records {
+		if record.Status.Done() {
+			delete(s.memory.records, key)
+		}
+	}
+	records, _ := s.memory.Unfinished(context.Background())
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	s.file, err = os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0)
+	return err
+}
+
+// Save creates or replaces the record of a task. The Input of a record
+// longer than load reads, e.g. a submit carrying a large imageBase64, is left
+// out; a record still too long is refused.
+func (s *FileTaskStore) Save(ctx context.Context, record TaskRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if len(line) > maxJournalLine && record.Input != nil {
+		record.Input = nil
+		if line, err = json.Marshal(record); err != nil {
+			return err
+		}
+	}
+	if len(line) > maxJournalLine {
+		return fmt.Errorf("aidge: record of task %s is %d bytes, over the %d of the journal", record.ID, len(line), maxJournalLine)
+	}
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+	if s.file == nil {
+		return errors.New("aidge: task store is closed")
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.memory.records[taskKey{record.API, record.ID}] = record
+	return nil
+}
+
+// Load returns the record of task id of api, or ErrTaskNotFound.
+func (s *FileTaskStore) Load(ctx context.Context, api, id string) (TaskRecord, error) {
+	return s.memory.Load(ctx, api, id)
+}
+
+// Unfinished returns the records of the tasks that are not done, oldest
+// first.
+func (s *FileTaskStore) Unfinished(ctx context.Context) ([]TaskRecord, error) {
+	return s.memory.Unfinished(ctx)
+}
+
+// Close closes the journal.
+func (s *FileTaskStore) Close() error {
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
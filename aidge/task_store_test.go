@@ -0,0 +1,258 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// runningRecord returns the record of a running task of spec.
+func runningRecord(spec aidge.TaskSpec, id string) aidge.TaskRecord {
+	now := time.Now()
+	return aidge.TaskRecord{
+		ID:          id,
+		API:         spec.SubmitAPI,
+		Status:      aidge.TaskRunning,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+}
+
+func TestResumeEndsTasksTheGatewayDoesNotKnow(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualModelTask
+	srv.Reply(spec.ResultAPI, aidgetest.Fail("InvalidParameter", "task not found"))
+
+	ctx := context.Background()
+	store := aidge.NewMemoryTaskStore()
+	store.Save(ctx, runningRecord(spec, "gone"))
+	client := srv.Client(aidge.WithTaskStore(store), aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	records, err := client.Resume(ctx)
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != aidge.TaskFailed || records[0].Error == "" {
+		t.Errorf("records = %+v, want the task failed with the refusal", records)
+	}
+	if unfinished, _ := store.Unfinished(ctx); len(unfinished) != 0 {
+		t.Errorf("unfinished tasks = %+v, want none", unfinished)
+	}
+}
+
+func TestResumeKeepsTasksOnAuthFailures(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualModelTask
+	srv.Reply(spec.ResultAPI, aidgetest.Fail("InvalidAppKey", "app key is invalid"))
+
+	ctx := context.Background()
+	store := aidge.NewMemoryTaskStore()
+	store.Save(ctx, runningRecord(spec, "kept"))
+	client := srv.Client(aidge.WithTaskStore(store), aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	if _, err := client.Resume(ctx); err == nil {
+		t.Error("Resume succeeded, want the auth failure")
+	}
+	if unfinished, _ := store.Unfinished(ctx); len(unfinished) != 1 {
+		t.Errorf("unfinished tasks = %+v, want the task kept", unfinished)
+	}
+}
+
+// brokenStore is a TaskStore that cannot save.
+type brokenStore struct {
+	*aidge.MemoryTaskStore
+}
+
+func (brokenStore) Save(ctx context.Context, record aidge.TaskRecord) error {
+	return errors.New("disk full")
+}
+
+func TestSubmitReturnsTheTaskWhenRecordingFails(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   aidge.TaskSpec
+		submit func(ctx context.Context, client *aidge.Client) (string, error)
+	}{
+		{"ImageTranslationPro", aidge.ImageTranslationProTask, func(ctx context.Context, client *aidge.Client) (string, error) {
+			batch, err := client.ImageTranslationPro.SubmitBatch(ctx, []aidge.ImageTranslationProItem{
+				{ImageURL: "https://example.com/a.jpg", SourceLanguage: "en", TargetLanguage: "fr"},
+			})
+			if batch == nil {
+				return "", err
+			}
+			return batch.TaskID(), err
+		}},
+		{"HandFootRepair", aidge.HandFootRepairTask, func(ctx context.Context, client *aidge.Client) (string, error) {
+			batch, err := client.HandFootRepair.Submit(ctx, []aidge.RepairItem{
+				{Area: aidge.AreaHand, ImageURL: "https://example.com/a.jpg"},
+			})
+			if batch == nil {
+				return "", err
+			}
+			return batch.TaskID(), err
+		}},
+		{"VirtualTryOn", aidge.VirtualTryOnProTask, func(ctx context.Context, client *aidge.Client) (string, error) {
+			task, err := client.VirtualTryOn.Submit(ctx, aidge.TryOnRequest{
+				Clothes: []aidge.Clothing{{ImageURL: "https://example.com/a.jpg", Type: aidge.ClothingTops}},
+			})
+			if task == nil {
+				return "", err
+			}
+			return task.ID(), err
+		}},
+		{"VirtualModel", aidge.VirtualModelTask, func(ctx context.Context, client *aidge.Client) (string, error) {
+			task, err := client.VirtualModel.Submit(ctx, aidge.VirtualModelRequest{
+				Image:           aidge.ImageURL("https://example.com/a.jpg"),
+				Dimension:       768,
+				Age:             aidge.AgeYouth,
+				BackgroundStyle: aidge.BackgroundRoom,
+				Ethnicity:       aidge.EthnicityWhite,
+				Gender:          aidge.GenderFemale,
+				ImageStyle:      aidge.ImageStyleRealPhoto,
+				Count:           1,
+			})
+			if task == nil {
+				return "", err
+			}
+			return task.ID(), err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := aidgetest.NewServer()
+			defer srv.Close()
+			srv.Task(tt.spec, aidgetest.Running())
+			client := srv.Client(aidge.WithTaskStore(brokenStore{aidge.NewMemoryTaskStore()}))
+
+			id, err := tt.submit(context.Background(), client)
+			var recordErr *aidge.TaskRecordError
+			if !errors.As(err, &recordErr) || recordErr.TaskID != id {
+				t.Errorf("Submit: %v, want a *TaskRecordError for task %q", err, id)
+			}
+			if id == "" {
+				t.Error("no task returned, want the submitted task")
+			}
+		})
+	}
+}
+
+// journalLine returns record as a line of a FileTaskStore journal.
+func journalLine(t *testing.T, record aidge.TaskRecord) string {
+	t.Helper()
+	line, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(line) + "\n"
+}
+
+func TestFileTaskStoreIgnoresTruncatedLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	line := journalLine(t, runningRecord(aidge.VirtualModelTask, "kept"))
+	truncated := journalLine(t, runningRecord(aidge.VirtualModelTask, "torn"))
+	if err := os.WriteFile(path, []byte(line+truncated[:len(truncated)/2]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := aidge.OpenFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileTaskStore: %v", err)
+	}
+	defer store.Close()
+	unfinished, _ := store.Unfinished(context.Background())
+	if len(unfinished) != 1 || unfinished[0].ID != "kept" {
+		t.Errorf("unfinished tasks = %+v, want the complete record", unfinished)
+	}
+}
+
+func TestFileTaskStoreKeepsTasksWithOversizeInput(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := aidge.OpenFileTaskStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	huge := runningRecord(aidge.VirtualModelTask, "huge")
+	huge.Input = json.RawMessage(`{"imageBase64":"` + strings.Repeat("A", 17<<20) + `"}`)
+	if err := store.Save(ctx, huge); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	store.Save(ctx, runningRecord(aidge.VirtualModelTask, "small"))
+	store.Close()
+
+	store, err = aidge.OpenFileTaskStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileTaskStore: %v", err)
+	}
+	defer store.Close()
+	unfinished, _ := store.Unfinished(ctx)
+	if len(unfinished) != 2 {
+		t.Fatalf("unfinished tasks = %+v, want both tasks after a reopen", unfinished)
+	}
+	record, err := store.Load(ctx, huge.API, huge.ID)
+	if err != nil {
+		t.Fatalf("Load of the oversize task: %v", err)
+	}
+	if record.Input != nil {
+		t.Errorf("Input of the oversize task is %d bytes, want it left out", len(record.Input))
+	}
+}
+
+func TestFileTaskStoreCompactionDropsFinishedTasks(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := aidge.OpenFileTaskStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finished := runningRecord(aidge.VirtualModelTask, "finished")
+	finished.Input = json.RawMessage(`{"imageBase64":"AAAA"}`)
+	store.Save(ctx, finished)
+	store.Save(ctx, runningRecord(aidge.VirtualModelTask, "running"))
+	finished.Status = aidge.TaskFinished
+	store.Save(ctx, finished)
+	store.Close()
+
+	store, err = aidge.OpenFileTaskStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	journal, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(journal, []byte("\n")); n != 1 || !bytes.Contains(journal, []byte(`"running"`)) {
+		t.Errorf("compacted journal = %s, want only the running task", journal)
+	}
+	if _, err := store.Load(ctx, finished.API, finished.ID); !errors.Is(err, aidge.ErrTaskNotFound) {
+		t.Errorf("Load of the finished task: %v, want ErrTaskNotFound", err)
+	}
+}
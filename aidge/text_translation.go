@@ -0,0 +1,123 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TextTranslationAPI is the Text Translation API.
+const TextTranslationAPI = "/ai/text/marco/translator"
+
+// FormatType is the format of the texts to translate.
+type FormatType string
+
+const (
+	// FormatText is plain text.
+	FormatText FormatType = "text"
+
+	// FormatHTML is HTML, whose markup is kept as is.
+	FormatHTML FormatType = "html"
+)
+
+// TextTranslationService calls the Text Translation API.
+type TextTranslationService struct {
+	client *Client
+}
+
+// TranslateRequest is a request to translate texts.
+type TranslateRequest struct {
+	// Texts are the texts to translate.
+	Texts []string
+
+	// SourceLanguage is the language of Texts.
+	SourceLanguage Language
+
+	// TargetLanguage is the language to translate to.
+	TargetLanguage Language
+
+	// FormatType is the format of Texts, FormatText or FormatHTML, FormatText
+	// when empty.
+	FormatType FormatType
+}
+
+// validate checks that r can be sent to the API.
+func (r *TranslateRequest) validate() error {
+	if len(r.Texts) == 0 {
+		return fmt.Errorf("%w: no texts to translate", ErrInvalidRequest)
+	}
+	if r.SourceLanguage == "" || r.TargetLanguage == "" {
+		return fmt.Errorf("%w: source and target languages are required", ErrInvalidRequest)
+	}
+	switch r.FormatType {
+	case "", FormatText, FormatHTML:
+	default:
+		return fmt.Errorf("%w: format type %q is neither %q nor %q", ErrInvalidRequest, r.FormatType, FormatText, FormatHTML)
+	}
+	return nil
+}
+
+// TranslatedText is the translation of one text.
+type TranslatedText struct {
+	// Source is the text that was translated.
+	Source string `json:"source"`
+
+	// Text is the translation.
+	Text string `json:"text"`
+}
+
+// Translate translates req.Texts. The result has one entry per text, in the
+// order of req.Texts.
+func (s *TextTranslationService) Translate(ctx context.Context, req TranslateRequest) ([]TranslatedText, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	format := req.FormatType
+	if format == "" {
+		format = FormatText
+	}
+
+	// The API takes the texts as a JSON encoded array inside the JSON body.
+	texts, err := json.Marshal(req.Texts)
+	if err != nil {
+		return nil, fmt.Errorf("aidge: %s: encoding texts: %w", TextTranslationAPI, err)
+	}
+	request := map[string]string{
+		"text":           string(texts),
+		"sourceLanguage": string(req.SourceLanguage),
+		"targetLanguage": string(req.TargetLanguage),
+		"formatType":     string(format),
+	}
+
+	var data struct {
+		TranslatedList []string `json:"translatedList"`
+	}
+	if err := s.client.Do(ctx, TextTranslationAPI, request, &data); err != nil {
+		return nil, err
+	}
+	if len(data.TranslatedList) != len(req.Texts) {
+		return nil, fmt.Errorf("aidge: %s: got %d translations for %d texts", TextTranslationAPI, len(data.TranslatedList), len(req.Texts))
+	}
+
+	translated := make([]TranslatedText, len(req.Texts))
+	for i, text := range req.Texts {
+		translated[i] = TranslatedText{Source: text, Text: data.TranslatedList[i]}
+	}
+	return translated, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Defaults of BatchOptions.
+const (
+	DefaultBatchMaxItems    = 50
+	DefaultBatchMaxBytes    = 10000
+	DefaultBatchConcurrency = 4
+)
+
+// BatchOptions controls how TranslateBatch splits the texts into calls.
+type BatchOptions struct {
+	// MaxItems is the largest number of texts in one call,
+	// DefaultBatchMaxItems when zero.
+	MaxItems int
+
+	// MaxBytes is the largest JSON encoded size of the texts of one call,
+	// DefaultBatchMaxBytes when zero. A longer text is sent on its own.
+	MaxBytes int
+
+	// Concurrency is the number of calls in flight,
+	// DefaultBatchConcurrency when zero. The rate limits of the client apply
+	// on top of it.
+	Concurrency int
+}
+
+// BatchTranslation is the outcome of TranslateBatch for one text.
+type BatchTranslation struct {
+	// Index is the position of the text in the request.
+	Index int `json:"index"`
+
+	// Source is the text that was translated.
+	Source string `json:"source"`
+
+	// Text is the translation, empty when Err is set.
+	Text string `json:"text,omitempty"`
+
+	// Err is the failure of the call that held the text.
+	Err error `json:"-"`
+}
+
+// TranslateBatch translates any number of texts by splitting them into
+// calls, made concurrently, of at most opts.MaxItems texts and opts.MaxBytes
+// bytes. The result has one entry per text, in the order of req.Texts. A
+// failed call is reported in the Err of its texts rather than failing the
+// batch. When the API rejects a call as invalid, its texts are bisected down
+// to single texts to single out the offending ones, unless both halves of a
+// split are rejected alike: a request rejected as a whole, e.g. for its
+// languages, takes three calls per chunk. TranslateBatch returns an
+// error only when req is invalid or ctx is done, along with the texts
+// translated so far.
+func (s *TextTranslationService) TranslateBatch(ctx context.Context, req TranslateRequest, opts BatchOptions) ([]BatchTranslation, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]BatchTranslation, len(req.Texts))
+	for i, text := range req.Texts {
+		results[i] = BatchTranslation{Index: i, Source: text}
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for _, chunk := range splitTexts(req.Texts, opts) {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			for _, i := range chunk {
+				results[i].Err = ctx.Err()
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(chunk []int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.translateChunk(ctx, req, chunk, results)
+		}(chunk)
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
+
+// translateChunk translates the texts at the indexes of chunk into results.
+func (s *TextTranslationService) translateChunk(ctx context.Context, req TranslateRequest, chunk []int, results []BatchTranslation) {
+	if err := s.translateTexts(ctx, req, chunk, results); err != nil {
+		s.bisect(ctx, req, chunk, results, err)
+	}
+}
+
+// bisect reports err, the failure of the call translating chunk, or splits
+// chunk in halves, down to single texts, to find the texts the API rejects
+// when err blames the parameters. When both halves are rejected for the same
+// reason, the request itself is taken to be at fault and err is reported for
+// the whole chunk.
+func (s *TextTranslationService) bisect(ctx context.Context, req TranslateRequest, chunk []int, results []BatchTranslation, err error) {
+	if len(chunk) == 1 || !invalidParameter(err) {
+		for _, index := range chunk {
+			results[index].Err = err
+		}
+		return
+	}
+	halves := [][]int{chunk[:len(chunk)/2], chunk[len(chunk)/2:]}
+	errs := make([]error, len(halves))
+	for i, half := range halves {
+		errs[i] = s.translateTexts(ctx, req, half, results)
+	}
+	if sameRejection(errs[0], errs[1]) {
+		for _, index := range chunk {
+			results[index].Err = err
+		}
+		return
+	}
+	for i, half := range halves {
+		if errs[i] != nil {
+			s.bisect(ctx, req, half, results, errs[i])
+		}
+	}
+}
+
+// translateTexts makes one call translating the texts at the indexes of
+// chunk into results.
+func (s *TextTranslationService) translateTexts(ctx context.Context, req TranslateRequest, chunk []int, results []BatchTranslation) error {
+	sub := req
+	sub.Texts = make([]string, len(chunk))
+	for i, index := range chunk {
+		sub.Texts[i] = req.Texts[index]
+	}
+	translated, err := s.Translate(ctx, sub)
+	if err != nil {
+		return err
+	}
+	for i, index := range chunk {
+		results[index].Text = translated[i].Text
+	}
+	return nil
+}
+
+// invalidParameter reports whether err is the API rejecting the parameters.
+func invalidParameter(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Kind == KindInvalidParameter
+}
+
+// sameRejection reports whether a and b are both the API rejecting the
+// parameters with the same code and message.
+func sameRejection(a, b error) bool {
+	var errA, errB *APIError
+	return invalidParameter(a) && invalidParameter(b) &&
+		errors.As(a, &errA) && errors.As(b, &errB) &&
+		errA.Code == errB.Code && errA.Message == errB.Message
+}
+
+// splitTexts groups the indexes of texts into chunks within the limits of
+// opts, keeping their order.
+func splitTexts(texts []string, opts BatchOptions) [][]int {
+	maxItems, maxBytes := opts.MaxItems, opts.MaxBytes
+	if maxItems <= 0 {
+		maxItems = DefaultBatchMaxItems
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultBatchMaxBytes
+	}
+
+	var chunks [][]int
+	var chunk []int
+	size := 0
+	for i, text := range texts {
+		encoded, _ := json.Marshal(text)
+		// One more byte for the comma between texts.
+		n := len(encoded) + 1
+		if len(chunk) > 0 && (len(chunk) == maxItems || size+n > maxBytes) {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 0
+		}
+		chunk = append(chunk, i)
+		size += n
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
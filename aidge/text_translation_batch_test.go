@@ -0,0 +1,189 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// translator scripts the Text Translation API to upper case the texts,
+// rejecting the calls holding one of the bad texts or targeting "xx". It
+// returns the texts of each call.
+func translator(srv *aidgetest.Server, bad ...string) func() [][]string {
+	var mu sync.Mutex
+	var calls [][]string
+	srv.HandleFunc(aidge.TextTranslationAPI, func(call aidgetest.Call) aidgetest.Reply {
+		var params map[string]string
+		var texts []string
+		if err := json.Unmarshal(call.Body, &params); err != nil {
+			return aidgetest.Fail("InvalidParameter", err.Error())
+		}
+		if err := json.Unmarshal([]byte(params["text"]), &texts); err != nil {
+			return aidgetest.Fail("InvalidParameter", err.Error())
+		}
+		mu.Lock()
+		calls = append(calls, texts)
+		mu.Unlock()
+
+		if params["targetLanguage"] == "xx" {
+			return aidgetest.Fail("InvalidParameter", "unsupported target language")
+		}
+		translated := make([]string, len(texts))
+		for i, text := range texts {
+			for _, b := range bad {
+				if text == b {
+					return aidgetest.Fail("InvalidParameter", "bad text: "+text)
+				}
+			}
+			translated[i] = strings.ToUpper(text)
+		}
+		return aidgetest.OK(map[string][]string{"translatedList": translated})
+	})
+	return func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+}
+
+func TestTranslateBatchSplitsInOrder(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	calls := translator(srv)
+
+	texts := []string{"a", "b", "c", "d", "e", strings.Repeat("long ", 10), "f", "g"}
+	results, err := srv.Client().TextTranslation.TranslateBatch(context.Background(), aidge.TranslateRequest{
+		Texts:          texts,
+		SourceLanguage: aidge.English,
+		TargetLanguage: aidge.French,
+	}, aidge.BatchOptions{MaxItems: 3, MaxBytes: 20, Concurrency: 1})
+	if err != nil {
+		t.Fatalf("TranslateBatch: %v", err)
+	}
+
+	var sent []string
+	for _, call := range calls() {
+		size := 0
+		for _, text := range call {
+			encoded, _ := json.Marshal(text)
+			size += len(encoded) + 1
+		}
+		if len(call) > 3 || (len(call) > 1 && size > 20) {
+			t.Errorf("call of %q exceeds the limits", call)
+		}
+		sent = append(sent, call...)
+	}
+	if strings.Join(sent, ",") != strings.Join(texts, ",") {
+		t.Errorf("texts sent as %q, want %q", sent, texts)
+	}
+	for i, r := range results {
+		if r.Index != i || r.Source != texts[i] || r.Text != strings.ToUpper(texts[i]) || r.Err != nil {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+}
+
+func TestTranslateBatchSinglesOutBadTexts(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	calls := translator(srv, "bad")
+
+	texts := []string{"a", "b", "c", "bad", "e", "f", "g", "h"}
+	results, err := srv.Client().TextTranslation.TranslateBatch(context.Background(), aidge.TranslateRequest{
+		Texts:          texts,
+		SourceLanguage: aidge.English,
+		TargetLanguage: aidge.French,
+	}, aidge.BatchOptions{})
+	if err != nil {
+		t.Fatalf("TranslateBatch: %v", err)
+	}
+	for i, r := range results {
+		if texts[i] == "bad" {
+			var apiErr *aidge.APIError
+			if !errors.As(r.Err, &apiErr) || apiErr.Kind != aidge.KindInvalidParameter {
+				t.Errorf("result %d: Err = %v, want the invalid parameter error", i, r.Err)
+			}
+		} else if r.Err != nil || r.Text != strings.ToUpper(texts[i]) {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+	// One call for the chunk, then two per halving down to the bad text.
+	if n := len(calls()); n != 7 {
+		t.Errorf("%d calls, want 7", n)
+	}
+}
+
+func TestTranslateBatchSinglesOutBadTextsInBothHalves(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	translator(srv, "bad1", "bad2")
+
+	texts := []string{"a", "bad1", "c", "d", "e", "f", "bad2", "h"}
+	results, err := srv.Client().TextTranslation.TranslateBatch(context.Background(), aidge.TranslateRequest{
+		Texts:          texts,
+		SourceLanguage: aidge.English,
+		TargetLanguage: aidge.French,
+	}, aidge.BatchOptions{})
+	if err != nil {
+		t.Fatalf("TranslateBatch: %v", err)
+	}
+	for i, r := range results {
+		bad := strings.HasPrefix(texts[i], "bad")
+		if bad && r.Err == nil {
+			t.Errorf("result %d has no error", i)
+		} else if !bad && (r.Err != nil || r.Text != strings.ToUpper(texts[i])) {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+}
+
+func TestTranslateBatchInvalidRequestFailsEveryText(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	calls := translator(srv)
+
+	texts := make([]string, 50)
+	for i := range texts {
+		texts[i] = "text"
+	}
+	results, err := srv.Client().TextTranslation.TranslateBatch(context.Background(), aidge.TranslateRequest{
+		Texts:          texts,
+		SourceLanguage: aidge.English,
+		TargetLanguage: "xx",
+	}, aidge.BatchOptions{})
+	if err != nil {
+		t.Fatalf("TranslateBatch: %v", err)
+	}
+	for i, r := range results {
+		var apiErr *aidge.APIError
+		if !errors.As(r.Err, &apiErr) || apiErr.Message != "unsupported target language" {
+			t.Errorf("result %d: Err = %v, want the unsupported target language", i, r.Err)
+		}
+	}
+	// One call for the chunk and one per half, rejected alike.
+	if n := len(calls()); n != 3 {
+		t.Errorf("%d calls, want 3", n)
+	}
+}
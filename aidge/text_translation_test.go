@@ -0,0 +1,104 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// sentBody returns the JSON body of the only call to apiName received by
+// srv.
+func sentBody(t *testing.T, srv *aidgetest.Server, apiName string) map[string]interface{} {
+	t.Helper()
+	calls := srv.CallsTo(apiName)
+	if len(calls) != 1 {
+		t.Fatalf("%d calls to %s, want 1", len(calls), apiName)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(calls[0].Body, &body); err != nil {
+		t.Fatalf("body of %s %q: %v", apiName, calls[0].Body, err)
+	}
+	return body
+}
+
+// checkBody reports the parameters of body that differ from want.
+func checkBody(t *testing.T, body, want map[string]interface{}) {
+	t.Helper()
+	for name, v := range want {
+		if body[name] != v {
+			t.Errorf("%s = %#v, want %#v", name, body[name], v)
+		}
+	}
+	for name := range body {
+		if _, ok := want[name]; !ok {
+			t.Errorf("unexpected parameter %s = %#v", name, body[name])
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.TextTranslationAPI, aidgetest.OK(map[string][]string{"translatedList": {"bonjour", "monde"}}))
+
+	translated, err := srv.Client().TextTranslation.Translate(context.Background(), aidge.TranslateRequest{
+		Texts:          []string{"hello", "world"},
+		SourceLanguage: aidge.English,
+		TargetLanguage: aidge.French,
+	})
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	checkBody(t, sentBody(t, srv, aidge.TextTranslationAPI), map[string]interface{}{
+		"text":           `["hello","world"]`,
+		"sourceLanguage": "en",
+		"targetLanguage": "fr",
+		"formatType":     "text",
+	})
+	want := []aidge.TranslatedText{{Source: "hello", Text: "bonjour"}, {Source: "world", Text: "monde"}}
+	if len(translated) != 2 || translated[0] != want[0] || translated[1] != want[1] {
+		t.Errorf("Translate = %+v, want %+v", translated, want)
+	}
+}
+
+func TestTranslateRejectsUnknownFormats(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	req := aidge.TranslateRequest{
+		Texts:          []string{"hello"},
+		SourceLanguage: aidge.English,
+		TargetLanguage: aidge.French,
+		FormatType:     "markdown",
+	}
+	if _, err := client.TextTranslation.Translate(context.Background(), req); !errors.Is(err, aidge.ErrInvalidRequest) {
+		t.Errorf("Translate: %v, want ErrInvalidRequest", err)
+	}
+	if _, err := client.TextTranslation.TranslateBatch(context.Background(), req, aidge.BatchOptions{}); !errors.Is(err, aidge.ErrInvalidRequest) {
+		t.Errorf("TranslateBatch: %v, want ErrInvalidRequest", err)
+	}
+	if calls := srv.CallsTo(aidge.TextTranslationAPI); len(calls) != 0 {
+		t.Errorf("%d calls to the API, want none", len(calls))
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ModelAge is the age group of a generated model.
+type ModelAge string
+
+const (
+	// AgeChild is a child model.
+	AgeChild ModelAge = "CHILD"
+
+	// AgeYouth is a young adult model.
+	AgeYouth ModelAge = "YOUTH"
+
+	// AgeMiddle is a middle-aged model.
+	AgeMiddle ModelAge = "MIDDLE"
+
+	// AgeElderly is an elderly model.
+	AgeElderly ModelAge = "ELDERLY"
+)
+
+// BackgroundStyle is the scene behind a generated model.
+type BackgroundStyle string
+
+const (
+	// BackgroundRoom is an indoor scene.
+	BackgroundRoom BackgroundStyle = "room"
+
+	// BackgroundStreet is an urban outdoor scene.
+	BackgroundStreet BackgroundStyle = "street"
+
+	// BackgroundNature is a natural outdoor scene.
+	BackgroundNature BackgroundStyle = "nature"
+)
+
+// ModelEthnicity is the appearance of a generated model.
+type ModelEthnicity string
+
+const (
+	// EthnicityWhite is a model of European appearance.
+	EthnicityWhite ModelEthnicity = "WHITE"
+
+	// EthnicityBlack is a model of African appearance.
+	EthnicityBlack ModelEthnicity = "BLACK"
+
+	// EthnicityAsian is a model of Asian appearance.
+	EthnicityAsian ModelEthnicity = "ASIAN"
+
+	// EthnicityLatino is a model of Latin American appearance.
+	EthnicityLatino ModelEthnicity = "LATINO"
+)
+
+// Gender is the gender of a generated model.
+type Gender string
+
+const (
+	// GenderFemale is a female model.
+	GenderFemale Gender = "FEMALE"
+
+	// GenderMale is a male model.
+	GenderMale Gender = "MALE"
+)
+
+// ImageStyle is the rendering style of generated images.
+type ImageStyle string
+
+// ImageStyleRealPhoto renders photographs.
+const ImageStyleRealPhoto ImageStyle = "realPhoto"
+
+// VirtualModelRequest is a request to replace the model wearing a garment
+// with a generated one.
+type VirtualModelRequest struct {
+	// Image is the photo of the model wearing the garment.
+	Image Image
+
+	// MaskKeepBackground keeps the background of Image instead of
+	// generating one in BackgroundStyle.
+	MaskKeepBackground bool
+
+	// Dimension is the size in pixels of the generated images, e.g. 768.
+	Dimension int
+
+	// Age, BackgroundStyle, Ethnicity, Gender and ImageStyle describe the
+	// generated model. Values other than the constants of their types are
+	// sent as is, for the API to judge.
+	Age             ModelAge
+	BackgroundStyle BackgroundStyle
+	Ethnicity       ModelEthnicity
+	Gender          Gender
+	ImageStyle      ImageStyle
+
+	// Count is the number of images to generate.
+	Count int
+}
+
+// Validate checks that the request is complete. The values themselves are
+// left to the API.
+func (r *VirtualModelRequest) Validate() error {
+	if err := r.Image.validate(); err != nil {
+		return err
+	}
+	switch {
+	case r.Dimension <= 0:
+		return fmt.Errorf("%w: dimension must be positive", ErrInvalidRequest)
+	case r.Count < 1:
+		return fmt.Errorf("%w: count must be positive", ErrInvalidRequest)
+	case r.Age == "":
+		return fmt.Errorf("%w: no age", ErrInvalidRequest)
+	case r.BackgroundStyle == "":
+		return fmt.Errorf("%w: no background style", ErrInvalidRequest)
+	case r.Ethnicity == "":
+		return fmt.Errorf("%w: no model", ErrInvalidRequest)
+	case r.Gender == "":
+		return fmt.Errorf("%w: no gender", ErrInvalidRequest)
+	case r.ImageStyle == "":
+		return fmt.Errorf("%w: no image style", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// VirtualModelImage is a generated image.
+type VirtualModelImage struct {
+	ImageURL string `json:"imageUrl"`
+}
+
+// VirtualModelResult is the result of a finished model generation task.
+type VirtualModelResult struct {
+	Images []VirtualModelImage `json:"result"`
+}
+
+// VirtualModelService calls the Virtual Model Alternation API.
+type VirtualModelService struct {
+	client *Client
+}
+
+// Submit validates and submits req and returns the generation task. The
+// submit is only retried when opts include Idempotent.
+func (s *VirtualModelService) Submit(ctx context.Context, req VirtualModelRequest, opts ...CallOption) (*Task[VirtualModelResult], error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The API takes every parameter as a string.
+	request := map[string]string{
+		"imageUrl":    req.Image.URL,
+		"imageBase64": req.Image.base64(),
+		"maskKeepBg":  strconv.FormatBool(req.MaskKeepBackground),
+		"dimension":   strconv.Itoa(req.Dimension),
+		"age":         string(req.Age),
+		"bgStyle":     string(req.BackgroundStyle),
+		"model":       string(req.Ethnicity),
+		"gender":      string(req.Gender),
+		"imageStyle":  string(req.ImageStyle),
+		"count":       strconv.Itoa(req.Count),
+	}
+	task := NewTask[VirtualModelResult](s.client, VirtualModelTask)
+	err := task.Submit(ctx, request, opts...)
+	return submitted(err, func() *Task[VirtualModelResult] { return task })
+}
+
+// Run submits req, waits for the task to finish and returns the generated
+// images.
+func (s *VirtualModelService) Run(ctx context.Context, req VirtualModelRequest, opts ...CallOption) (*VirtualModelResult, error) {
+	task, err := s.Submit(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := task.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
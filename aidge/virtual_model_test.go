@@ -0,0 +1,92 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestVirtualModel(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualModelTask
+	srv.Task(spec, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]string{{"imageUrl": "https://example.com/model1.png"}, {"imageUrl": "https://example.com/model2.png"}},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	image := []byte("local image")
+	result, err := client.VirtualModel.Run(context.Background(), aidge.VirtualModelRequest{
+		Image:              aidge.ImageBytes(image),
+		MaskKeepBackground: true,
+		Dimension:          768,
+		Age:                aidge.AgeYouth,
+		BackgroundStyle:    aidge.BackgroundRoom,
+		Ethnicity:          aidge.EthnicityWhite,
+		Gender:             aidge.GenderFemale,
+		ImageStyle:         aidge.ImageStyleRealPhoto,
+		Count:              2,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// The API takes every parameter as a string.
+	checkBody(t, sentBody(t, srv, spec.SubmitAPI), map[string]interface{}{
+		"imageUrl":    "",
+		"imageBase64": base64.StdEncoding.EncodeToString(image),
+		"maskKeepBg":  "true",
+		"dimension":   "768",
+		"age":         "YOUTH",
+		"bgStyle":     "room",
+		"model":       "WHITE",
+		"gender":      "FEMALE",
+		"imageStyle":  "realPhoto",
+		"count":       "2",
+	})
+	if len(result.Images) != 2 || result.Images[1].ImageURL != "https://example.com/model2.png" {
+		t.Errorf("Run = %+v, want the two generated images", result)
+	}
+}
+
+func TestVirtualModelPassesValuesThrough(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Task(aidge.VirtualModelTask, aidgetest.Running())
+
+	_, err := srv.Client().VirtualModel.Submit(context.Background(), aidge.VirtualModelRequest{
+		Image:           aidge.ImageURL("https://example.com/a.jpg"),
+		Dimension:       1024,
+		Age:             "TEEN",
+		BackgroundStyle: "beach",
+		Ethnicity:       "MIXED",
+		Gender:          aidge.GenderMale,
+		ImageStyle:      "studio",
+		Count:           8,
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v, want values unknown to the package left to the API", err)
+	}
+	if body := sentBody(t, srv, aidge.VirtualModelTask.SubmitAPI); body["age"] != "TEEN" || body["count"] != "8" {
+		t.Errorf("body = %v", body)
+	}
+}
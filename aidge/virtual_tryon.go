@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ClothingType is the kind of a garment to try on. Values other than the
+// constants are sent as is, for the API to judge.
+type ClothingType string
+
+// ClothingTops are shirts, blouses, jackets and other upper garments.
+const ClothingTops ClothingType = "tops"
+
+// ModelGender is the gender of the model wearing the clothes.
+type ModelGender string
+
+const (
+	// ModelFemale is a female model.
+	ModelFemale ModelGender = "female"
+
+	// ModelMale is a male model.
+	ModelMale ModelGender = "male"
+)
+
+// ModelBody is the body shape of the model. Values other than the constants
+// are sent as is, for the API to judge.
+type ModelBody string
+
+// BodySlim is a slim model.
+const BodySlim ModelBody = "slim"
+
+// ModelStyle is the look of the model, e.g. "universal_1".
+type ModelStyle string
+
+// StyleUniversal1 is the default model style.
+const StyleUniversal1 ModelStyle = "universal_1"
+
+// ModelBaseGeneral is the general model base.
+const ModelBaseGeneral = "General"
+
+// ViewType is the camera view of the generated images. Values other than the
+// constants are sent as is, for the API to judge.
+type ViewType string
+
+// ViewMixed lets the API choose the view of each image.
+const ViewMixed ViewType = "mixed"
+
+// Clothing is a garment to try on.
+type Clothing struct {
+	// ImageURL is the image of the garment, accessible from the public
+	// network, larger than 500x500 and at most 3000x3000 pixels.
+	ImageURL string `json:"imageUrl"`
+
+	// Type is the kind of the garment.
+	Type ClothingType `json:"type"`
+}
+
+// TryOnModel describes the model wearing the clothes.
+type TryOnModel struct {
+	// Base is the model base, ModelBaseGeneral when empty.
+	Base string `json:"base"`
+
+	Gender ModelGender `json:"gender"`
+	Style  ModelStyle  `json:"style"`
+	Body   ModelBody   `json:"body"`
+}
+
+// TryOnRequest is a request to dress a model in clothes.
+type TryOnRequest struct {
+	// Clothes are the garments to try on together.
+	Clothes []Clothing
+
+	// Model is the model wearing the clothes.
+	Model TryOnModel
+
+	// ViewType is the camera view, ViewMixed when empty.
+	ViewType ViewType
+
+	// InputQualityDetect rejects clothing images of poor quality.
+	InputQualityDetect bool
+
+	// GenerateCount is the number of images to generate, 1 when zero.
+	GenerateCount int
+}
+
+// TryOnImage is a generated image.
+type TryOnImage struct {
+	ImageURL string `json:"imageUrl"`
+}
+
+// TryOnResult is the result of a finished try-on task.
+type TryOnResult struct {
+	Images []TryOnImage `json:"result"`
+}
+
+// VirtualTryOnService calls the Virtual Try-On Pro API.
+type VirtualTryOnService struct {
+	client *Client
+}
+
+// Submit submits req and returns the try-on task. The submit is only
+// retried when opts include Idempotent.
+func (s *VirtualTryOnService) Submit(ctx context.Context, req TryOnRequest, opts ...CallOption) (*Task[TryOnResult], error) {
+	if len(req.Clothes) == 0 {
+		return nil, fmt.Errorf("%w: no clothes to try on", ErrInvalidRequest)
+	}
+	for i, clothing := range req.Clothes {
+		if clothing.ImageURL == "" || clothing.Type == "" {
+			return nil, fmt.Errorf("%w: clothing %d needs an image and a type", ErrInvalidRequest, i)
+		}
+	}
+	if req.GenerateCount < 0 {
+		return nil, fmt.Errorf("%w: negative generate count", ErrInvalidRequest)
+	}
+
+	model := req.Model
+	if model.Base == "" {
+		model.Base = ModelBaseGeneral
+	}
+	params := struct {
+		ClothesList        []Clothing `json:"clothesList"`
+		Model              TryOnModel `json:"model"`
+		ViewType           ViewType   `json:"viewType"`
+		InputQualityDetect int        `json:"inputQualityDetect"`
+		GenerateCount      int        `json:"generateCount"`
+	}{
+		ClothesList:   req.Clothes,
+		Model:         model,
+		ViewType:      req.ViewType,
+		GenerateCount: max(req.GenerateCount, 1),
+	}
+	if params.ViewType == "" {
+		params.ViewType = ViewMixed
+	}
+	if req.InputQualityDetect {
+		params.InputQualityDetect = 1
+	}
+
+	// The API takes a JSON encoded array of requests inside the JSON body.
+	requestParams, err := json.Marshal([]interface{}{params})
+	if err != nil {
+		return nil, fmt.Errorf("aidge: %s: encoding request: %w", VirtualTryOnProTask.SubmitAPI, err)
+	}
+	task := NewTask[TryOnResult](s.client, VirtualTryOnProTask)
+	err = task.Submit(ctx, map[string]string{"requestParams": string(requestParams)}, opts...)
+	return submitted(err, func() *Task[TryOnResult] { return task })
+}
+
+// Run submits req, waits for the task to finish and returns the generated
+// images.
+func (s *VirtualTryOnService) Run(ctx context.Context, req TryOnRequest, opts ...CallOption) (*TryOnResult, error) {
+	task, err := s.Submit(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := task.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aidge_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+func TestTryOn(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualTryOnProTask
+	srv.Task(spec, aidgetest.Running(), aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]string{{"imageUrl": "https://example.com/tryon.png"}},
+	}))
+	client := srv.Client(aidge.WithPoll(aidge.FixedPoll(time.Millisecond)))
+
+	result, err := client.VirtualTryOn.Run(context.Background(), aidge.TryOnRequest{
+		Clothes: []aidge.Clothing{
+			{ImageURL: "https://example.com/top.jpg", Type: aidge.ClothingTops},
+			{ImageURL: "https://example.com/skirt.jpg", Type: "skirts"},
+		},
+		Model:              aidge.TryOnModel{Gender: aidge.ModelFemale, Style: aidge.StyleUniversal1, Body: aidge.BodySlim},
+		InputQualityDetect: true,
+		GenerateCount:      2,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// The API takes a JSON encoded array of requests in requestParams.
+	body := sentBody(t, srv, spec.SubmitAPI)
+	encoded, ok := body["requestParams"].(string)
+	if !ok || len(body) != 1 {
+		t.Fatalf("body = %v, want only the requestParams string", body)
+	}
+	var params []interface{}
+	if err := json.Unmarshal([]byte(encoded), &params); err != nil {
+		t.Fatalf("requestParams %q: %v", encoded, err)
+	}
+	var want []interface{}
+	json.Unmarshal([]byte(`[{
+		"clothesList": [
+			{"imageUrl": "https://example.com/top.jpg", "type": "tops"},
+			{"imageUrl": "https://example.com/skirt.jpg", "type": "skirts"}
+		],
+		"model": {"base": "General", "gender": "female", "style": "universal_1", "body": "slim"},
+		"viewType": "mixed",
+		"inputQualityDetect": 1,
+		"generateCount": 2
+	}]`), &want)
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("requestParams = %s, want %v", encoded, want)
+	}
+
+	queries := srv.CallsTo(spec.ResultAPI)
+	if len(queries) != 2 {
+		t.Fatalf("%d result queries, want 2", len(queries))
+	}
+	var query map[string]string
+	if json.Unmarshal(queries[0].Body, &query) != nil || query["task_id"] == "" {
+		t.Errorf("result query %s, want the task_id", queries[0].Body)
+	}
+	if len(result.Images) != 1 || result.Images[0].ImageURL != "https://example.com/tryon.png" {
+		t.Errorf("Run = %+v, want the generated image", result)
+	}
+}
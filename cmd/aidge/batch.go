@@ -0,0 +1,421 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
+
+// batchLine is the line written to the output for a row of the input.
+type batchLine struct {
+	// Row is the position of the row in the input, from 1.
+	Row int `json:"row"`
+
+	Input  json.RawMessage `json:"input"`
+	Status string          `json:"status"`
+
+	// TaskID is the task of an asynchronous API.
+	TaskID string          `json:"taskId,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Code   string          `json:"code,omitempty"`
+}
+
+// Statuses of batchLine. A pending row has a submitted task that is not
+// done yet; its line is written as soon as the task is submitted, so that
+// the next run waits for the task rather than submitting the row again if
+// the run stops before it is done.
+const (
+	statusOK      = "ok"
+	statusFailed  = "failed"
+	statusPending = "pending"
+)
+
+// batchRow is a row of the input.
+type batchRow struct {
+	row   int
+	input json.RawMessage
+
+	// cells are the cells of a CSV row by column.
+	cells map[string]string
+}
+
+func (c *cli) batchRun(ctx context.Context, args []string) error {
+	flags := c.flagSet("batch run", "-api name -input file -output file [flags]\n\n"+
+		"Each row of the input is the request body of a call, with the parameters listed by\n"+
+		"\"aidge apis -json\": a CSV file has a header naming the parameters, a JSONL file one\n"+
+		"JSON object per line. The CSV rows of these APIs have flat columns instead:"+
+		rowTasksUsage()+"\n\n"+
+		"One line per row is appended to the output, in JSON. Rows\n"+
+		"already in the output with the same request are skipped, so an interrupted run is\n"+
+		"resumed by running it again. A row is written as pending as soon as its task is\n"+
+		"submitted, and the next run waits for that task if it was not done; the last line\n"+
+		"of a row is its result.")
+	api := flags.String("api", "", "name or path of the API, e.g. image-translation")
+	input := flags.String("input", "", "CSV or JSONL file of the requests")
+	outputFile := flags.String("output", "", "JSONL file of the results")
+	format := flags.String("format", "", `format of the input, "csv" or "jsonl", from its extension when empty`)
+	concurrency := flags.Int("concurrency", 4, "number of rows in flight")
+	qps := flags.Float64("qps", 0, "calls per second to the submit and to the result API each, no limit other than the client's when zero")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if *api == "" || *input == "" || *outputFile == "" {
+		return usagef("aidge: -api, -input and -output are required")
+	}
+	service, ok := aidge.LookupService(*api)
+	if !ok {
+		return usagef("aidge: unknown API %q, see \"aidge apis\"", *api)
+	}
+	if *concurrency < 1 {
+		return usagef("aidge: -concurrency must be at least 1")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*input)), ".")
+	}
+
+	rows, err := readRows(*input, *format)
+	if err != nil {
+		return err
+	}
+	previous, err := readOutput(*outputFile)
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(*outputFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("aidge: %w", err)
+	}
+	defer out.Close()
+	if err := endLine(out); err != nil {
+		return fmt.Errorf("aidge: %w", err)
+	}
+
+	client := c.client
+	if *qps > 0 {
+		limit := aidge.RateLimit{QPS: *qps, Burst: 1}
+		opts := []aidge.Option{aidge.WithAPIRateLimit(service.Path, limit)}
+		if spec, async := service.Task(); async {
+			opts = append(opts, aidge.WithAPIRateLimit(spec.ResultAPI, limit))
+		}
+		client = aidge.NewClient(c.client.Config(), opts...)
+	}
+
+	var (
+		mu       sync.Mutex
+		enc      = json.NewEncoder(out)
+		writeErr error
+		failed   int
+		pending  int
+		skipped  int
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, *concurrency)
+	)
+	enc.SetEscapeHTML(false)
+	write := func(line batchLine) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := enc.Encode(line); err != nil && writeErr == nil {
+			writeErr = fmt.Errorf("aidge: writing results: %w", err)
+		}
+	}
+	for _, r := range rows {
+		prev, done := previous[r.row]
+		if done && !sameInput(prev.Input, r.input) {
+			// The row was edited since, its line is of another request.
+			prev, done = batchLine{}, false
+		}
+		if done && prev.Status != statusPending {
+			skipped++
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(r batchRow, taskID string) {
+			defer func() { <-sem; wg.Done() }()
+			line, err := runRow(ctx, client, service, r, taskID, func(taskID string) {
+				write(batchLine{Row: r.row, Input: r.input, Status: statusPending, TaskID: taskID})
+			})
+			if err != nil && (ctx.Err() != nil || errors.Is(err, aidge.ErrWaitExceeded)) {
+				if line.TaskID != "" {
+					// The pending line of the task is already written.
+					mu.Lock()
+					pending++
+					mu.Unlock()
+				}
+				// Otherwise nothing was submitted, leave the row to the
+				// next run.
+				return
+			}
+			if line.Status == statusFailed {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+			write(line)
+		}(r, prev.TaskID)
+	}
+	wg.Wait()
+
+	if writeErr != nil {
+		return writeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("aidge: batch interrupted, run it again to resume: %w", err)
+	}
+	fmt.Fprintf(c.stderr, "aidge: %d rows, %d skipped, %d failed, %d pending\n", len(rows), skipped, failed, pending)
+	if failed > 0 {
+		return fmt.Errorf("aidge: %d rows failed, see %s", failed, *outputFile)
+	}
+	if pending > 0 {
+		return fmt.Errorf("aidge: %d rows pending, run the batch again to wait for them", pending)
+	}
+	return nil
+}
+
+// runRow calls service with the request of r, or waits for taskID, the task
+// already submitted for r, and returns the line of r and its failure. When
+// it submits a task, submitted is called with its id before waiting for it.
+func runRow(ctx context.Context, client *aidge.Client, service aidge.ServiceInfo, r batchRow, taskID string, submitted func(taskID string)) (batchLine, error) {
+	line := batchLine{Row: r.row, Input: r.input, Status: statusOK, TaskID: taskID}
+	var err error
+	if task, ok := rowTasks[service.Name]; ok && r.cells != nil {
+		if taskID == "" {
+			line.TaskID, err = task.submit(ctx, client, r.cells)
+			if line.TaskID != "" {
+				submitted(line.TaskID)
+			}
+		}
+		if err == nil {
+			var result interface{}
+			if result, err = task.wait(ctx, client, r.cells, line.TaskID); err == nil {
+				line.Result, err = json.Marshal(result)
+			}
+		}
+	} else {
+		err = callRow(ctx, client, service, r, &line, submitted)
+	}
+	if err != nil {
+		line.Status = statusFailed
+		line.Result = nil
+		line.Error = err.Error()
+		var apiErr *aidge.APIError
+		var taskErr *aidge.TaskFailedError
+		switch {
+		case errors.As(err, &taskErr):
+			line.Code = taskErr.Code
+		case errors.As(err, &apiErr):
+			line.Code = apiErr.Code
+		}
+	}
+	return line, err
+}
+
+// callRow calls service with the request body of r, or waits for
+// line.TaskID, and stores the result in line. When it submits a task,
+// submitted is called with its id before waiting for it.
+func callRow(ctx context.Context, client *aidge.Client, service aidge.ServiceInfo, r batchRow, line *batchLine, submitted func(taskID string)) error {
+	input := r.input
+	if r.cells != nil {
+		var err error
+		if input, err = coerceCells(service, r.cells); err != nil {
+			return err
+		}
+	}
+	if err := checkParams(service, input); err != nil {
+		return err
+	}
+	spec, async := service.Task()
+	if !async {
+		return client.Do(ctx, service.Path, input, &line.Result)
+	}
+	task := aidge.AttachTask[json.RawMessage](client, spec, line.TaskID)
+	if line.TaskID == "" {
+		task = aidge.NewTask[json.RawMessage](client, spec)
+		err := task.Submit(ctx, input)
+		line.TaskID = task.ID()
+		if line.TaskID != "" {
+			submitted(line.TaskID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	var err error
+	line.Result, err = task.Wait(ctx)
+	return err
+}
+
+// checkParams checks that input has the required parameters of service.
+func checkParams(service aidge.ServiceInfo, input json.RawMessage) error {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Errorf("%w: the row is not a JSON object", aidge.ErrInvalidRequest)
+	}
+	for _, p := range service.Params {
+		if v, ok := params[p.Name]; p.Required && (!ok || string(v) == `""` || string(v) == "null") {
+			return fmt.Errorf("%w: missing %s", aidge.ErrInvalidRequest, p.Name)
+		}
+	}
+	return nil
+}
+
+// readRows reads the rows of the input file.
+func readRows(name, format string) ([]batchRow, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("aidge: %w", err)
+	}
+	defer f.Close()
+
+	switch format {
+	case "csv":
+		rows, err := readCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf("aidge: reading %s: %w", name, err)
+		}
+		return rows, nil
+	case "jsonl", "ndjson":
+		rows, err := readJSONL(f)
+		if err != nil {
+			return nil, fmt.Errorf("aidge: reading %s: %w", name, err)
+		}
+		return rows, nil
+	}
+	return nil, usagef("aidge: unknown input format %q, use -format csv or jsonl", format)
+}
+
+// readCSV reads the rows of a CSV file with a header naming the parameters.
+// Empty cells are left out of the request.
+func readCSV(r io.Reader) ([]batchRow, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	rows := make([]batchRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		params := map[string]string{}
+		for j, value := range record {
+			if value != "" {
+				params[header[j]] = value
+			}
+		}
+		input, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, batchRow{row: i + 1, input: input, cells: params})
+	}
+	return rows, nil
+}
+
+// readJSONL reads the rows of a file holding one JSON object per line. Blank
+// lines are skipped but counted.
+func readJSONL(r io.Reader) ([]batchRow, error) {
+	var rows []batchRow
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !json.Valid([]byte(line)) {
+			return nil, fmt.Errorf("line %d is not valid JSON", n)
+		}
+		rows = append(rows, batchRow{row: n, input: json.RawMessage(line)})
+	}
+	return rows, scanner.Err()
+}
+
+// sameInput reports whether a and b are the same JSON request, as written
+// to the output and read from the input.
+func sameInput(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// readOutput returns the last line of each row in the output file. A
+// truncated last line, left by an interrupted run, is ignored. The line of
+// a row is only to be trusted when its Input is the request of the row.
+func readOutput(name string) (map[int]batchLine, error) {
+	lines := map[int]batchLine{}
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return lines, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aidge: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var line batchLine
+		if json.Unmarshal(scanner.Bytes(), &line) == nil && line.Row > 0 {
+			lines[line.Row] = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("aidge: reading %s: %w", name, err)
+	}
+	return lines, nil
+}
+
+// endLine ends the truncated last line of f, if any, so that the lines
+// appended to it are whole.
+func endLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte("\n"))
+	}
+	return err
+}
@@ -0,0 +1,188 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// readLines returns the lines of the output file.
+func readLines(t *testing.T, name string) []batchLine {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []batchLine
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line batchLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestBatchRunResumesPendingTasks(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualModelTask
+	srv.Task(spec, aidgetest.Running())
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "items.jsonl")
+	output := filepath.Join(dir, "results.jsonl")
+	if err := os.WriteFile(input, []byte(`{"imageUrl":"https://example.com/a.jpg"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"batch", "run", "-api", "virtual-model", "-input", input, "-output", output}
+
+	// The task is still running when the run times out.
+	if code := runAidge(t, srv, append([]string{"-timeout", "300ms"}, args...)...); code != exitTimeout {
+		t.Errorf("interrupted run exited with %d, want %d", code, exitTimeout)
+	}
+	lines := readLines(t, output)
+	if len(lines) != 1 || lines[0].Status != statusPending || lines[0].TaskID == "" {
+		t.Fatalf("lines after the interrupted run = %+v, want one pending line with the task id", lines)
+	}
+
+	srv.Reply(spec.ResultAPI, aidgetest.OK(map[string]interface{}{
+		"taskStatus": "finished",
+		"result":     []map[string]string{{"imageUrl": "https://example.com/model.png"}},
+	}))
+	if code := runAidge(t, srv, args...); code != exitOK {
+		t.Errorf("resumed run exited with %d", code)
+	}
+	if n := len(srv.CallsTo(spec.SubmitAPI)); n != 1 {
+		t.Errorf("%d submits, want 1", n)
+	}
+	lines = readLines(t, output)
+	if len(lines) != 2 || lines[1].Status != statusOK || lines[1].TaskID != lines[0].TaskID {
+		t.Errorf("lines after the resumed run = %+v, want the task finished", lines)
+	}
+
+	// Nothing is left to do.
+	if code := runAidge(t, srv, args...); code != exitOK || len(readLines(t, output)) != 2 {
+		t.Errorf("third run exited with %d and wrote %d lines", code, len(readLines(t, output)))
+	}
+}
+
+func TestBatchRunCoercesCSVCells(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]interface{}{"imageUrl": "https://example.com/big.png"}))
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "items.csv")
+	output := filepath.Join(dir, "results.jsonl")
+	if err := os.WriteFile(input, []byte("imageUrl,upscaleFactor\nhttps://example.com/a.jpg,4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := runAidge(t, srv, "batch", "run", "-api", "image-upscaling", "-input", input, "-output", output); code != exitOK {
+		t.Errorf("run exited with %d", code)
+	}
+	calls := srv.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("%d calls, want 1", len(calls))
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(calls[0].Body, &params); err != nil {
+		t.Fatal(err)
+	}
+	if params["upscaleFactor"] != 4.0 {
+		t.Errorf("upscaleFactor = %#v, want the number 4", params["upscaleFactor"])
+	}
+}
+
+func TestBatchRunMapsCSVRowsOfTypedServices(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.ImageTranslationProTask
+	srv.Task(spec, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]interface{}{{"imageUrl": "https://example.com/fr.png", "success": true}},
+	}))
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "items.csv")
+	output := filepath.Join(dir, "results.jsonl")
+	if err := os.WriteFile(input, []byte("imageUrl,sourceLanguage,targetLanguage\nhttps://example.com/a.jpg,en,fr\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := runAidge(t, srv, "batch", "run", "-api", "image-translation-pro", "-input", input, "-output", output); code != exitOK {
+		t.Errorf("run exited with %d", code)
+	}
+	submits := srv.CallsTo(spec.SubmitAPI)
+	if len(submits) != 1 {
+		t.Fatalf("%d submits, want 1", len(submits))
+	}
+	var params struct {
+		ParamJSON string `json:"paramJson"`
+	}
+	if err := json.Unmarshal(submits[0].Body, &params); err != nil {
+		t.Fatal(err)
+	}
+	var items []aidge.ImageTranslationProItem
+	if err := json.Unmarshal([]byte(params.ParamJSON), &items); err != nil {
+		t.Fatalf("paramJson %q: %v", params.ParamJSON, err)
+	}
+	want := aidge.ImageTranslationProItem{ImageURL: "https://example.com/a.jpg", SourceLanguage: "en", TargetLanguage: "fr"}
+	if len(items) != 1 || items[0] != want {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+	// The pending line of the task, written once submitted, then its result.
+	lines := readLines(t, output)
+	if len(lines) != 2 || lines[0].Status != statusPending || lines[0].TaskID == "" ||
+		lines[1].Status != statusOK || string(lines[1].Result) != `{"imageUrl":"https://example.com/fr.png"}` {
+		t.Errorf("lines = %+v", lines)
+	}
+}
+
+func TestBatchRunRedoesEditedRows(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	srv.Reply(aidge.ImageUpscalingAPI, aidgetest.OK(map[string]interface{}{"imageUrl": "https://example.com/big.png"}))
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "items.jsonl")
+	output := filepath.Join(dir, "results.jsonl")
+	args := []string{"batch", "run", "-api", "image-upscaling", "-input", input, "-output", output}
+	for i, row := range []string{`{"imageUrl":"https://example.com/a.jpg","upscaleFactor":2}`, `{"imageUrl":"https://example.com/b.jpg","upscaleFactor":2}`} {
+		if err := os.WriteFile(input, []byte(row+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if code := runAidge(t, srv, args...); code != exitOK {
+			t.Errorf("run %d exited with %d", i+1, code)
+		}
+	}
+	if n := len(srv.Calls()); n != 2 {
+		t.Errorf("%d calls, want the edited row called again", n)
+	}
+	lines := readLines(t, output)
+	if len(lines) != 2 || string(lines[1].Input) != `{"imageUrl":"https://example.com/b.jpg","upscaleFactor":2}` {
+		t.Errorf("lines = %+v, want a line for the edited row", lines)
+	}
+}
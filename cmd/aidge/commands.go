@@ -0,0 +1,368 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
+
+// flagSet returns the flag set of command name, printing usage and the flags
+// on -h.
+func (c *cli) flagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: aidge %s %s\n\nFlags:\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse parses args with flags. The flag package has already printed the
+// mistake, if any.
+func parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{}
+	}
+	return nil
+}
+
+// readImage returns the image at location, a URL or a local file.
+func readImage(location string) (aidge.Image, error) {
+	if location == "" {
+		return aidge.Image{}, usagef("aidge: -image is required")
+	}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return aidge.ImageURL(location), nil
+	}
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return aidge.Image{}, fmt.Errorf("aidge: %w", err)
+	}
+	return aidge.ImageBytes(data), nil
+}
+
+// translationOutput is a translated text as printed.
+type translationOutput struct {
+	aidge.BatchTranslation
+	Error string `json:"error,omitempty"`
+}
+
+func (c *cli) translateText(ctx context.Context, args []string) error {
+	flags := c.flagSet("translate text", "-from lang -to lang [text ...]\n\nThe texts are read from the standard input, one per line, when none are given.")
+	from := flags.String("from", "", "language code of the texts, e.g. en")
+	to := flags.String("to", "", "language code to translate to, e.g. zh")
+	format := flags.String("format", string(aidge.FormatText), `format of the texts, "text" or "html"`)
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return usagef("aidge: -from and -to are required")
+	}
+
+	texts := flags.Args()
+	if len(texts) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			texts = append(texts, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("aidge: reading texts: %w", err)
+		}
+	}
+
+	translations, err := c.client.TextTranslation.TranslateBatch(ctx, aidge.TranslateRequest{
+		Texts:          texts,
+		SourceLanguage: aidge.Language(*from),
+		TargetLanguage: aidge.Language(*to),
+		FormatType:     aidge.FormatType(*format),
+	}, aidge.BatchOptions{})
+	if err != nil && translations == nil {
+		return err
+	}
+	out := make([]translationOutput, len(translations))
+	for i, t := range translations {
+		out[i].BatchTranslation = t
+		if t.Err != nil {
+			out[i].Error = t.Err.Error()
+			if err == nil {
+				err = t.Err
+			}
+		}
+	}
+	if printErr := c.print(out); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func (c *cli) imageCutOut(ctx context.Context, args []string) error {
+	flags := c.flagSet("image cutout", "-image url|file [-background white|transparent]")
+	image := flags.String("image", "", "URL or local file of the image")
+	background := flags.String("background", "white", `background of the result, "white", "transparent" or a backGroundType value of the API`)
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	img, err := readImage(*image)
+	if err != nil {
+		return err
+	}
+	var backgroundType aidge.BackgroundType
+	switch *background {
+	case "white":
+		backgroundType = aidge.WhiteBackground
+	case "transparent":
+		backgroundType = aidge.TransparentBackground
+	default:
+		backgroundType = aidge.BackgroundType(*background)
+	}
+
+	result, err := c.client.BackgroundRemoval.CutOut(ctx, aidge.CutOutRequest{
+		Image:          img,
+		BackgroundType: backgroundType,
+	})
+	if err != nil {
+		return err
+	}
+	return c.print(result)
+}
+
+func (c *cli) imageUpscale(ctx context.Context, args []string) error {
+	flags := c.flagSet("image upscale", "-image url|file [-factor 2|3|4]")
+	image := flags.String("image", "", "URL or local file of the image")
+	factor := flags.Int("factor", 2, "upscale factor, 2, 3 or 4")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	img, err := readImage(*image)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.client.ImageUpscaling.Upscale(ctx, aidge.UpscaleRequest{
+		Image:  img,
+		Factor: *factor,
+	})
+	if err != nil {
+		return err
+	}
+	return c.print(result)
+}
+
+// clothingFlag collects garments of one type, or of the type given with
+// each URL as type=url when typ is empty.
+type clothingFlag struct {
+	clothes *[]aidge.Clothing
+	typ     aidge.ClothingType
+}
+
+func (f clothingFlag) String() string {
+	return ""
+}
+
+func (f clothingFlag) Set(value string) error {
+	typ, url := f.typ, value
+	if typ == "" {
+		t, u, ok := strings.Cut(value, "=")
+		if !ok || t == "" || u == "" {
+			return fmt.Errorf("%q is not type=url", value)
+		}
+		typ, url = aidge.ClothingType(t), u
+	}
+	*f.clothes = append(*f.clothes, aidge.Clothing{ImageURL: url, Type: typ})
+	return nil
+}
+
+func (c *cli) tryOn(ctx context.Context, args []string) error {
+	flags := c.flagSet("tryon", "-top url | -clothing type=url [flags]")
+	var req aidge.TryOnRequest
+	flags.Var(clothingFlag{&req.Clothes, aidge.ClothingTops}, "top", "URL of a top to try on")
+	flags.Var(clothingFlag{clothes: &req.Clothes}, "clothing", "garment of another type to try on, as type=url, the type sent as is")
+	gender := flags.String("gender", string(aidge.ModelFemale), `gender of the model, "female" or "male"`)
+	body := flags.String("body", string(aidge.BodySlim), `body of the model, e.g. "slim"`)
+	style := flags.String("style", string(aidge.StyleUniversal1), `style of the model, e.g. "universal_1"`)
+	view := flags.String("view", string(aidge.ViewMixed), `camera view, e.g. "mixed"`)
+	flags.IntVar(&req.GenerateCount, "count", 1, "number of images to generate")
+	flags.BoolVar(&req.InputQualityDetect, "quality-detect", false, "reject clothing images of poor quality")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if len(req.Clothes) == 0 {
+		return usagef("aidge: no clothes to try on, use -top or -clothing")
+	}
+	req.Model = aidge.TryOnModel{
+		Gender: aidge.ModelGender(*gender),
+		Body:   aidge.ModelBody(*body),
+		Style:  aidge.ModelStyle(*style),
+	}
+	req.ViewType = aidge.ViewType(*view)
+
+	result, err := c.client.VirtualTryOn.Run(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(result)
+}
+
+// repairOutput is the repair of an image as printed.
+type repairOutput struct {
+	ImageURL  string   `json:"imageUrl"`
+	ImageURLs []string `json:"imageUrls,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func (c *cli) handFootRepair(ctx context.Context, args []string) error {
+	flags := c.flagSet("hand-foot-repair", "[-area hand|foot] [-count n] image-url ...")
+	area := flags.String("area", string(aidge.AreaHand), `body part to repair, "hand" or "foot"`)
+	count := flags.Int("count", 1, "number of repaired images to generate per image")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usagef("aidge: no images to repair")
+	}
+	items := make([]aidge.RepairItem, flags.NArg())
+	for i, url := range flags.Args() {
+		items[i] = aidge.RepairItem{
+			Area:       aidge.RepairArea(*area),
+			ImageURL:   url,
+			ImageCount: *count,
+		}
+	}
+
+	results, err := c.client.HandFootRepair.Repair(ctx, items)
+	if err != nil {
+		return err
+	}
+	out := make([]repairOutput, len(results))
+	for i, r := range results {
+		out[i] = repairOutput{ImageURL: r.Item.ImageURL, ImageURLs: r.ImageURLs}
+		if r.Err != nil {
+			out[i].Error = r.Err.Error()
+			if err == nil {
+				err = r.Err
+			}
+		}
+	}
+	if printErr := c.print(out); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func (c *cli) call(ctx context.Context, args []string) error {
+	flags := c.flagSet("call", "<api> [-data json|@file] [-no-wait]\n\n<api> is the path of an API, e.g. /ai/image/cut/out, or its name in \"aidge apis\".\nThe result of an asynchronous API is waited for unless -no-wait is set.")
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if err := parse(flags, args); err != nil {
+			return err
+		}
+		return usagef("aidge: no API to call")
+	}
+	apiName := args[0]
+	data := flags.String("data", "{}", "JSON body of the request, or @file to read it from a file, @- from the standard input")
+	noWait := flags.Bool("no-wait", false, "print the task id of an asynchronous API instead of waiting for its result")
+	if err := parse(flags, args[1:]); err != nil {
+		return err
+	}
+
+	body := []byte(*data)
+	if strings.HasPrefix(*data, "@") {
+		var err error
+		if *data == "@-" {
+			body, err = io.ReadAll(os.Stdin)
+		} else {
+			body, err = os.ReadFile(strings.TrimPrefix(*data, "@"))
+		}
+		if err != nil {
+			return fmt.Errorf("aidge: reading request: %w", err)
+		}
+	}
+	if !json.Valid(body) {
+		return usagef("aidge: the request is not valid JSON")
+	}
+
+	service, ok := aidge.LookupService(apiName)
+	if !ok {
+		var result json.RawMessage
+		if err := c.client.Do(ctx, apiName, json.RawMessage(body), &result); err != nil {
+			return err
+		}
+		return c.print(result)
+	}
+	spec, async := service.Task()
+	switch {
+	case !async && service.Method == http.MethodGet:
+		return usagef("aidge: %s is not called with a JSON body", service.Path)
+	case !async:
+		var result json.RawMessage
+		if err := c.client.Do(ctx, service.Path, json.RawMessage(body), &result); err != nil {
+			return err
+		}
+		return c.print(result)
+	}
+
+	task := aidge.NewTask[json.RawMessage](c.client, spec)
+	if err := task.Submit(ctx, json.RawMessage(body)); err != nil {
+		return err
+	}
+	if *noWait {
+		return c.print(map[string]string{"taskId": task.ID()})
+	}
+	result, err := task.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("%w (task %s)", err, task.ID())
+	}
+	return c.print(result)
+}
+
+func (c *cli) listAPIs(args []string) error {
+	flags := c.flagSet("apis", "[-json]")
+	asJSON := flags.Bool("json", false, "print the full description of the APIs as JSON")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	services := aidge.Services()
+	if *asJSON {
+		return c.print(services)
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPATH\tMODE\tTITLE")
+	for _, s := range services {
+		mode := "sync"
+		if s.Async {
+			mode = "async"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, s.Path, mode, s.Title)
+	}
+	return w.Flush()
+}
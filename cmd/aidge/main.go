@@ -0,0 +1,247 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command aidge calls Aidge APIs from the command line.
+//
+// It reads the API key from the accessKey and secret environment variables,
+// like the examples, and prints the results as JSON. See "aidge help" for the
+// commands and exit codes.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
+
+// Exit codes.
+const (
+	exitOK = iota
+	exitFailure
+	exitUsage
+	exitAuth
+	exitQuota
+	exitInvalid
+	exitThrottled
+	exitServer
+	exitTaskFailed
+	exitTimeout
+)
+
+const usage = `Usage: aidge [flags] <command> [arguments]
+
+Commands:
+  translate text      translate texts
+  image cutout        remove the background of an image
+  image upscale       upscale an image
+  tryon               dress a model in clothes
+  hand-foot-repair    repair the hands and feet of models
+  call <api>          call any API with a JSON body
+  batch run           call an API for each row of a CSV or JSONL file
+  apis                list the APIs
+
+Run "aidge <command> -h" for the flags of a command.
+
+The API key is read from the accessKey and secret environment variables.
+
+Flags:
+  -domain string   API domain, api.aidc-ai.com or cn-api.aidc-ai.com
+  -trial           use the trial resources of the account
+  -timeout d       maximum duration of the command, e.g. 10m, none by default
+
+Exit codes:
+  0  success
+  1  failure
+  2  usage error
+  3  authentication failed
+  4  calling resources exhausted
+  5  invalid request or parameter
+  6  throttled
+  7  server error
+  8  task failed
+  9  timed out
+`
+
+// cli is the environment of the commands.
+type cli struct {
+	client *aidge.Client
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command runs a command with its arguments.
+type command func(c *cli, ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"translate text":   (*cli).translateText,
+	"image cutout":     (*cli).imageCutOut,
+	"image upscale":    (*cli).imageUpscale,
+	"tryon":            (*cli).tryOn,
+	"hand-foot-repair": (*cli).handFootRepair,
+	"call":             (*cli).call,
+	"batch run":        (*cli).batchRun,
+}
+
+// usageError is a mistake in the command line.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("aidge", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	domain := flags.String("domain", aidge.DefaultApiDomain, "")
+	trial := flags.Bool("trial", false, "")
+	timeout := flags.Duration("timeout", 0, "")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	args = flags.Args()
+	if len(args) == 0 || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	if args[0] == "apis" {
+		c := &cli{stdout: stdout, stderr: stderr}
+		return report(c.listAPIs(args[1:]), stderr)
+	}
+
+	name, cmd := args[0], commands[args[0]]
+	if cmd == nil && len(args) > 1 {
+		name = args[0] + " " + args[1]
+		cmd = commands[name]
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "aidge: unknown command %q\n\n%s", strings.Join(args, " "), usage)
+		return exitUsage
+	}
+	args = args[len(strings.Fields(name)):]
+
+	accessKey, secret := os.Getenv("accessKey"), os.Getenv("secret")
+	if (accessKey == "" || secret == "") && !wantsHelp(args) {
+		fmt.Fprintln(stderr, "aidge: set the accessKey and secret environment variables")
+		return exitUsage
+	}
+	c := &cli{stdout: stdout, stderr: stderr}
+	c.client = aidge.NewClient(aidge.ApiConfig{
+		AccessKeyName:    accessKey,
+		AccessKeySecret:  secret,
+		ApiDomain:        *domain,
+		UseTrialResource: *trial,
+	})
+
+	// Stop on interrupt, batch run resumes where it stopped.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	return report(cmd(c, ctx, args), stderr)
+}
+
+// wantsHelp reports whether args ask for the usage of a command.
+func wantsHelp(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "-h", "-help", "--help":
+			return true
+		}
+	}
+	return false
+}
+
+// print prints v as indented JSON.
+func (c *cli) print(v interface{}) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// report prints err and returns its exit code.
+func report(err error, stderr io.Writer) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if msg := err.Error(); msg != "" {
+		fmt.Fprintln(stderr, msg)
+	}
+	return exitCode(err)
+}
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	var usageErr *usageError
+	var apiErr *aidge.APIError
+	var taskErr *aidge.TaskFailedError
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &taskErr):
+		return exitTaskFailed
+	case errors.As(err, &apiErr):
+		switch apiErr.Kind {
+		case aidge.KindAuth:
+			return exitAuth
+		case aidge.KindQuotaExhausted:
+			return exitQuota
+		case aidge.KindInvalidParameter:
+			return exitInvalid
+		case aidge.KindThrottled:
+			return exitThrottled
+		case aidge.KindServer:
+			return exitServer
+		}
+	case errors.Is(err, aidge.ErrInvalidRequest):
+		return exitInvalid
+	case errors.Is(err, aidge.ErrWaitExceeded), errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	}
+	return exitFailure
+}
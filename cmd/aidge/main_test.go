@@ -0,0 +1,110 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+	"github.com/Aidge-AI/aidge-go/aidge/aidgetest"
+)
+
+// runAidge runs the aidge command line args against srv and returns the
+// exit code.
+func runAidge(t *testing.T, srv *aidgetest.Server, args ...string) int {
+	t.Helper()
+	t.Setenv("accessKey", srv.AccessKeyName)
+	t.Setenv("secret", srv.AccessKeySecret)
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-domain", srv.URL}, args...), &stdout, &stderr)
+	t.Logf("exit %d: %s", code, stderr.String())
+	return code
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply aidgetest.Reply
+		args  []string
+		want  int
+	}{
+		{"unknown flag", aidgetest.OK(nil), []string{"-bogus"}, exitUsage},
+		{"unknown command flag", aidgetest.OK(nil), []string{"image", "upscale", "-bogus"}, exitUsage},
+		{"unknown command", aidgetest.OK(nil), []string{"frobnicate"}, exitUsage},
+		{"auth failure", aidgetest.Fail("InvalidSignature", "bad signature"),
+			[]string{"image", "upscale", "-image", "https://example.com/a.jpg"}, exitAuth},
+		{"quota exhausted", aidgetest.Fail("InsufficientResource", "Sorry, your calling resources have been exhausted"),
+			[]string{"image", "upscale", "-image", "https://example.com/a.jpg"}, exitQuota},
+		{"invalid parameter", aidgetest.Fail("InvalidParameter", "bad image"),
+			[]string{"image", "upscale", "-image", "https://example.com/a.jpg"}, exitInvalid},
+		{"invalid request", aidgetest.OK(nil),
+			[]string{"image", "upscale", "-image", "https://example.com/a.jpg", "-factor", "9"}, exitInvalid},
+		{"success", aidgetest.OK(map[string]string{"imageUrl": "https://example.com/big.png"}),
+			[]string{"image", "upscale", "-image", "https://example.com/a.jpg"}, exitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := aidgetest.NewServer()
+			defer srv.Close()
+			srv.Reply(aidge.ImageUpscalingAPI, tt.reply)
+			if code := runAidge(t, srv, tt.args...); code != tt.want {
+				t.Errorf("aidge %v exited with %d, want %d", tt.args, code, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryOnFlags(t *testing.T) {
+	srv := aidgetest.NewServer()
+	defer srv.Close()
+	spec := aidge.VirtualTryOnProTask
+	srv.Task(spec, aidgetest.Finished(map[string]interface{}{
+		"result": []map[string]string{{"imageUrl": "https://example.com/tryon.png"}},
+	}))
+
+	args := []string{"tryon", "-top", "https://example.com/top.jpg", "-clothing", "skirts=https://example.com/skirt.jpg"}
+	if code := runAidge(t, srv, args...); code != exitOK {
+		t.Fatalf("aidge %v exited with %d", args, code)
+	}
+	calls := srv.CallsTo(spec.SubmitAPI)
+	if len(calls) != 1 {
+		t.Fatalf("%d submits, want 1", len(calls))
+	}
+	var body map[string]string
+	var params []struct {
+		ClothesList []aidge.Clothing `json:"clothesList"`
+		Model       aidge.TryOnModel `json:"model"`
+	}
+	if err := json.Unmarshal(calls[0].Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(body["requestParams"]), &params); err != nil || len(params) != 1 {
+		t.Fatalf("requestParams %q: %v", body["requestParams"], err)
+	}
+	want := []aidge.Clothing{
+		{ImageURL: "https://example.com/top.jpg", Type: aidge.ClothingTops},
+		{ImageURL: "https://example.com/skirt.jpg", Type: "skirts"},
+	}
+	if got := params[0].ClothesList; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("clothesList = %+v, want %+v", got, want)
+	}
+	if params[0].Model.Body != aidge.BodySlim {
+		t.Errorf("body = %q, want %q by default", params[0].Model.Body, aidge.BodySlim)
+	}
+}
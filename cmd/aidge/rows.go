@@ -0,0 +1,223 @@
+/*
+Copyright (C) 2024 NEURALNETICS PTE. LTD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Aidge-AI/aidge-go/aidge"
+)
+
+// rowTask runs the CSV rows of an asynchronous API whose request nests its
+// parameters through the typed service of the API, so that each row holds
+// flat columns.
+type rowTask struct {
+	// columns lists the columns of a row.
+	columns string
+
+	// submit submits the row and returns the id of its task.
+	submit func(ctx context.Context, client *aidge.Client, cells map[string]string) (string, error)
+
+	// wait waits for the task of the row and returns its result.
+	wait func(ctx context.Context, client *aidge.Client, cells map[string]string, taskID string) (interface{}, error)
+}
+
+var rowTasks = map[string]rowTask{
+	"image-translation-pro": {
+		columns: "imageUrl, sourceLanguage, targetLanguage",
+		submit: func(ctx context.Context, client *aidge.Client, cells map[string]string) (string, error) {
+			batch, err := client.ImageTranslationPro.SubmitBatch(ctx, translationProItems(cells))
+			if batch == nil {
+				return "", err
+			}
+			return batch.TaskID(), err
+		},
+		wait: func(ctx context.Context, client *aidge.Client, cells map[string]string, taskID string) (interface{}, error) {
+			batch := client.ImageTranslationPro.AttachBatch(taskID, translationProItems(cells))
+			results, err := client.ImageTranslationPro.WaitBatch(ctx, batch)
+			if err != nil {
+				return nil, err
+			}
+			if results[0].Err != nil {
+				return nil, results[0].Err
+			}
+			return map[string]string{"imageUrl": results[0].ImageURL}, nil
+		},
+	},
+	"hand-foot-repair": {
+		columns: "imageUrl, area (hand or foot), imgNum, requestBizId",
+		submit: func(ctx context.Context, client *aidge.Client, cells map[string]string) (string, error) {
+			items, err := repairItems(cells)
+			if err != nil {
+				return "", err
+			}
+			batch, err := client.HandFootRepair.Submit(ctx, items)
+			if batch == nil {
+				return "", err
+			}
+			return batch.TaskID(), err
+		},
+		wait: func(ctx context.Context, client *aidge.Client, cells map[string]string, taskID string) (interface{}, error) {
+			items, err := repairItems(cells)
+			if err != nil {
+				return nil, err
+			}
+			results, err := client.HandFootRepair.Wait(ctx, client.HandFootRepair.Attach(taskID, items))
+			if err != nil {
+				return nil, err
+			}
+			if results[0].Err != nil {
+				return nil, results[0].Err
+			}
+			return map[string][]string{"imageUrls": results[0].ImageURLs}, nil
+		},
+	},
+	"virtual-tryon": {
+		columns: "topUrl, gender, body, style, viewType, generateCount, inputQualityDetect",
+		submit: func(ctx context.Context, client *aidge.Client, cells map[string]string) (string, error) {
+			req, err := tryOnRequest(cells)
+			if err != nil {
+				return "", err
+			}
+			task, err := client.VirtualTryOn.Submit(ctx, req)
+			if task == nil {
+				return "", err
+			}
+			return task.ID(), err
+		},
+		wait: func(ctx context.Context, client *aidge.Client, cells map[string]string, taskID string) (interface{}, error) {
+			return aidge.AttachTask[aidge.TryOnResult](client, aidge.VirtualTryOnProTask, taskID).Wait(ctx)
+		},
+	},
+}
+
+// rowTasksUsage describes the columns of the APIs of rowTasks.
+func rowTasksUsage() string {
+	var names []string
+	for name := range rowTasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n  %s: %s", name, rowTasks[name].columns)
+	}
+	return b.String()
+}
+
+func translationProItems(cells map[string]string) []aidge.ImageTranslationProItem {
+	return []aidge.ImageTranslationProItem{{
+		ImageURL:       cells["imageUrl"],
+		SourceLanguage: aidge.Language(cells["sourceLanguage"]),
+		TargetLanguage: aidge.Language(cells["targetLanguage"]),
+	}}
+}
+
+func repairItems(cells map[string]string) ([]aidge.RepairItem, error) {
+	count, err := intCell(cells, "imgNum")
+	if err != nil {
+		return nil, err
+	}
+	area := aidge.RepairArea(cells["area"])
+	if area == "" {
+		area = aidge.AreaHand
+	}
+	return []aidge.RepairItem{{
+		Area:         area,
+		ImageURL:     cells["imageUrl"],
+		ImageCount:   count,
+		RequestBizID: cells["requestBizId"],
+	}}, nil
+}
+
+func tryOnRequest(cells map[string]string) (aidge.TryOnRequest, error) {
+	req := aidge.TryOnRequest{
+		Model: aidge.TryOnModel{
+			Gender: aidge.ModelGender(cells["gender"]),
+			Body:   aidge.ModelBody(cells["body"]),
+			Style:  aidge.ModelStyle(cells["style"]),
+		},
+		ViewType: aidge.ViewType(cells["viewType"]),
+	}
+	if url := cells["topUrl"]; url != "" {
+		req.Clothes = append(req.Clothes, aidge.Clothing{ImageURL: url, Type: aidge.ClothingTops})
+	}
+	var err error
+	if req.GenerateCount, err = intCell(cells, "generateCount"); err != nil {
+		return req, err
+	}
+	if v := cells["inputQualityDetect"]; v != "" {
+		if req.InputQualityDetect, err = strconv.ParseBool(v); err != nil {
+			return req, fmt.Errorf("%w: inputQualityDetect %q is not a boolean", aidge.ErrInvalidRequest, v)
+		}
+	}
+	return req, nil
+}
+
+// intCell returns the number in column, zero when it is empty.
+func intCell(cells map[string]string, column string) (int, error) {
+	v := cells[column]
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s %q is not a number", aidge.ErrInvalidRequest, column, v)
+	}
+	return n, nil
+}
+
+// coerceCells returns the request body of a CSV row of service, with the
+// cells of numeric, boolean, array and object parameters decoded as such.
+// The other cells, and the parameters the API takes as strings, are sent as
+// strings.
+func coerceCells(service aidge.ServiceInfo, cells map[string]string) (json.RawMessage, error) {
+	types := map[string]string{}
+	for _, f := range service.Params {
+		types[f.Name] = f.Type
+	}
+	params := map[string]interface{}{}
+	for name, value := range cells {
+		switch types[name] {
+		case "integer", "number":
+			n := json.Number(value)
+			if _, err := n.Float64(); err != nil {
+				return nil, fmt.Errorf("%w: %s %q is not a number", aidge.ErrInvalidRequest, name, value)
+			}
+			params[name] = n
+		case "boolean":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %q is not a boolean", aidge.ErrInvalidRequest, name, value)
+			}
+			params[name] = b
+		case "array", "object":
+			if !json.Valid([]byte(value)) {
+				return nil, fmt.Errorf("%w: %s is not valid JSON", aidge.ErrInvalidRequest, name)
+			}
+			params[name] = json.RawMessage(value)
+		default:
+			params[name] = value
+		}
+	}
+	return json.Marshal(params)
+}
@@ -0,0 +1,3 @@
+module github.com/Aidge-AI/aidge-go
+
+go 1.21